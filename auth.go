@@ -0,0 +1,57 @@
+package health
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Authorizer reports whether the request is allowed to see the detailed health check response.
+type Authorizer func(r *http.Request) bool
+
+// BearerTokenAuthorizer approves the requests with "Authorization: Bearer <token>" header.
+func BearerTokenAuthorizer(token string) Authorizer {
+	return func(r *http.Request) bool {
+		auth := r.Header.Get("Authorization")
+		if len(auth) < len("Bearer ") || !strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+			return false
+		}
+
+		return secureCompare(auth[len("Bearer "):], token)
+	}
+}
+
+// BasicAuthAuthorizer approves the requests with the HTTP basic authentication credentials.
+func BasicAuthAuthorizer(username, password string) Authorizer {
+	return func(r *http.Request) bool {
+		u, p, ok := r.BasicAuth()
+		if !ok {
+			return false
+		}
+
+		// both are compared to not leak which one does not match through timing
+		usernameOK := secureCompare(u, username)
+		passwordOK := secureCompare(p, password)
+
+		return usernameOK && passwordOK
+	}
+}
+
+// authorized reports whether the request is allowed to see the detailed response
+func (h *Health) authorized(r *http.Request) bool {
+	return h.authorizer == nil || h.authorizer(r)
+}
+
+// minimal returns the summary with the status and its reason only
+func minimal(c Check) Check {
+	return Check{
+		Status:    c.Status,
+		Timestamp: c.Timestamp,
+		Reason:    c.Reason,
+	}
+}
+
+// secureCompare compares the strings in constant time
+func secureCompare(given, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBearerTokenAuthorizer(t *testing.T) {
+	authorize := BearerTokenAuthorizer("s3cr3t")
+
+	for header, expected := range map[string]bool{
+		"":                false,
+		"Bearer":          false,
+		"Bearer ":         false,
+		"Bearer s3cr3t":   true,
+		"bearer s3cr3t":   true,
+		"Bearer s3cr3t ":  false,
+		"Bearer other":    false,
+		"Basic czNjcjN0":  false,
+		"Bearer s3cr3t-1": false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+		req.Header.Set("Authorization", header)
+
+		assert.Equal(t, expected, authorize(req), "Authorization: %s", header)
+	}
+}
+
+func TestBasicAuthAuthorizer(t *testing.T) {
+	authorize := BasicAuthAuthorizer("admin", "s3cr3t")
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	assert.False(t, authorize(req))
+
+	req.SetBasicAuth("admin", "s3cr3t")
+	assert.True(t, authorize(req))
+
+	req.SetBasicAuth("admin", "other")
+	assert.False(t, authorize(req))
+
+	req.SetBasicAuth("other", "s3cr3t")
+	assert.False(t, authorize(req))
+}
+
+func TestHealthHandlerWithAuthorizer(t *testing.T) {
+	h, err := New(WithAuthorizer(BearerTokenAuthorizer("s3cr3t")), WithSystemInfo(), WithChecks(Config{
+		Name:  "mongodb",
+		Check: func(context.Context) error { return errors.New("dial tcp 10.0.0.12:27017: connection refused") },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+
+	var body map[string]any
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.NoError(t, err)
+	assert.Equal(t, string(StatusUnavailable), body["status"])
+	assert.NotContains(t, body, "failures")
+	assert.NotContains(t, body, "results")
+	assert.NotContains(t, body, "system")
+
+	res = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Contains(t, res.Body.String(), "10.0.0.12")
+
+	res = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/status?check=postgres", nil)
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.NotContains(t, res.Body.String(), "mongodb", "registered checks should not be disclosed")
+}
@@ -0,0 +1,149 @@
+package health
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// validateDependencies checks that the dependencies of the check are registered
+// and do not form a cycle. Must be called with h.mu held.
+func (h *Health) validateDependencies(c Config) error {
+	if cycle := h.findCycle(c); cycle != nil {
+		return fmt.Errorf("health check %q has a dependency cycle: %s", c.Name, strings.Join(cycle, " -> "))
+	}
+
+	for _, dep := range c.DependsOn {
+		if _, ok := h.checks[dep]; !ok {
+			return fmt.Errorf("health check %q depends on health check %q that is not registered", c.Name, dep)
+		}
+	}
+
+	return nil
+}
+
+// dependents returns the sorted names of the registered checks that depend on the check.
+// Must be called with h.mu held.
+func (h *Health) dependents(name string) []string {
+	var res []string
+	for _, c := range h.checks {
+		if slices.Contains(c.DependsOn, name) {
+			res = append(res, c.Name)
+		}
+	}
+	slices.Sort(res)
+
+	return res
+}
+
+// findCycle returns the dependency cycle that registering the check would introduce,
+// or nil if there is none. Must be called with h.mu held.
+func (h *Health) findCycle(c Config) []string {
+	// registered checks never form a cycle, so a new cycle must go through c
+	visited := make(map[string]bool)
+
+	var walk func(name string, path []string) []string
+	walk = func(name string, path []string) []string {
+		check, ok := h.checks[name]
+		if name == c.Name {
+			check, ok = c, true
+		}
+		if !ok {
+			return nil
+		}
+
+		for _, dep := range check.DependsOn {
+			depPath := append(path[:len(path):len(path)], dep)
+			if dep == c.Name {
+				return depPath
+			}
+			if visited[dep] {
+				continue
+			}
+
+			visited[dep] = true
+			if cycle := walk(dep, depPath); cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	return walk(c.Name, []string{c.Name})
+}
+
+// failedDependency returns the name of the first dependency of the check whose last result
+// is a hard failure, taking into account the check overrides, or empty string if there is none.
+// Must be called with h.mu held.
+func (h *Health) failedDependency(c Config) string {
+	for _, dep := range c.DependsOn {
+		if h.dependencyFailed(dep) {
+			return dep
+		}
+	}
+
+	return ""
+}
+
+// dependencyFailed reports whether the last result of the dependency is a hard failure, taking into account
+// the check overrides. Must be called with h.mu held.
+func (h *Health) dependencyFailed(dep string) bool {
+	state, ok := h.states[dep]
+	return ok && state.last != nil && h.overriddenResult(*state.last).failedHard()
+}
+
+// sortByDependencies orders the checks so that every check comes after its dependencies.
+// Dependencies that are not part of the given checks are ignored.
+// Cycles are left in the order of the names, to be rejected on registration.
+func sortByDependencies(checks []Config) []Config {
+	checks = slices.Clone(checks)
+	slices.SortFunc(checks, func(a, b Config) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// pending counts the checks not sorted yet by name, duplicates are rejected on registration
+	pending := make(map[string]int, len(checks))
+	for _, c := range checks {
+		pending[c.Name]++
+	}
+
+	done := make([]bool, len(checks))
+	sorted := make([]Config, 0, len(checks))
+	for len(sorted) < len(checks) {
+		added := false
+		for i, c := range checks {
+			if done[i] || slices.ContainsFunc(c.DependsOn, func(dep string) bool { return pending[dep] > 0 }) {
+				continue
+			}
+
+			sorted = append(sorted, c)
+			pending[c.Name]--
+			done[i] = true
+			added = true
+		}
+
+		if !added {
+			// the remaining checks form a cycle
+			for i, c := range checks {
+				if !done[i] {
+					sorted = append(sorted, c)
+				}
+			}
+			break
+		}
+	}
+
+	return sorted
+}
+
+// skippedResult returns the result of a check that was not executed because its dependency failed
+func skippedResult(c Config, dep string) checkResult {
+	return checkResult{
+		name:      c.Name,
+		skipOnErr: c.SkipOnErr,
+		skippedBy: dep,
+		startedAt: time.Now(),
+	}
+}
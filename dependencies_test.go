@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterDependencyCycle(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:      "a",
+		DependsOn: []string{"b"},
+	}, Config{
+		Name:      "b",
+		DependsOn: []string{"c"},
+	}, Config{
+		Name: "c",
+	}))
+	require.NoError(t, err)
+
+	err = h.Replace(Config{
+		Name:      "c",
+		DependsOn: []string{"a"},
+	})
+	require.EqualError(t, err, `health check "c" has a dependency cycle: c -> a -> b -> c`)
+
+	err = h.Register(Config{
+		Name:      "self",
+		DependsOn: []string{"self"},
+	})
+	require.Error(t, err)
+
+	_, err = New(WithChecks(Config{
+		Name:      "x",
+		DependsOn: []string{"y"},
+	}, Config{
+		Name:      "y",
+		DependsOn: []string{"x"},
+	}))
+	require.Error(t, err)
+}
+
+func TestRegisterUnknownDependency(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name: "network",
+	}))
+	require.NoError(t, err)
+
+	err = h.Register(Config{
+		Name:      "postgres",
+		DependsOn: []string{"netwrok"},
+	})
+	require.EqualError(t, err, `health check "postgres" depends on health check "netwrok" that is not registered`)
+
+	require.NoError(t, h.Register(Config{
+		Name:      "postgres",
+		DependsOn: []string{"network"},
+	}))
+
+	err = h.Replace(Config{
+		Name:      "postgres",
+		DependsOn: []string{"network", "dns"},
+	})
+	require.EqualError(t, err, `health check "postgres" depends on health check "dns" that is not registered`)
+
+	_, err = New(WithChecks(Config{
+		Name:      "replica",
+		DependsOn: []string{"postgres"},
+	}))
+	require.Error(t, err, "dependencies must be registered before or together with the check")
+}
+
+func TestUnregisterDependency(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:      "postgres",
+		DependsOn: []string{"network"},
+	}, Config{
+		Name:      "redis",
+		DependsOn: []string{"network"},
+	}, Config{
+		Name: "network",
+	}))
+	require.NoError(t, err)
+
+	err = h.Unregister("network")
+	require.EqualError(t, err, `health check "network" is a dependency of postgres, redis`)
+
+	require.NoError(t, h.Unregister("postgres"))
+	require.NoError(t, h.Replace(Config{Name: "redis"}))
+	require.NoError(t, h.Unregister("network"))
+}
+
+func TestHealth_MeasureDependencies(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	check := func(name string, err error) CheckFunc {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, name)
+			return err
+		}
+	}
+
+	h, err := New(WithChecks(Config{
+		Name:      "postgres",
+		DependsOn: []string{"network"},
+		Check:     check("postgres", nil),
+	}, Config{
+		Name:      "replica",
+		DependsOn: []string{"postgres"},
+		Check:     check("replica", nil),
+	}, Config{
+		Name:      "redis",
+		DependsOn: []string{"vpn"},
+		Check:     check("redis", nil),
+	}, Config{
+		Name:  "network",
+		Check: check("network", nil),
+	}, Config{
+		Name:      "vpn",
+		SkipOnErr: true,
+		Check:     check("vpn", errors.New("vpn is down")),
+	}), WithMaxConcurrent(1))
+	require.NoError(t, err)
+
+	result := h.Measure(context.Background())
+
+	assert.Equal(t, []string{"network", "postgres", "replica", "vpn"}, order)
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, map[string]string{
+		"vpn":   "vpn is down",
+		"redis": "skipped due to vpn",
+	}, result.Failures)
+	assert.Equal(t, StatusSkipped, result.Results["redis"].Status)
+	assert.Equal(t, StatusOK, result.Results["replica"].Status)
+}
+
+func TestHealth_MeasureDependencyNotSelected(t *testing.T) {
+	var calls atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name:   "network",
+		Probes: []Probe{ProbeLiveness},
+		Check:  func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:      "postgres",
+		DependsOn: []string{"network"},
+		Check: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	// the dependency has no result yet, so it is not considered failed
+	c := h.MeasureReadiness(context.Background())
+	assert.Equal(t, StatusOK, c.Results["postgres"].Status)
+	assert.Equal(t, int32(1), calls.Load())
+
+	h.MeasureLiveness(context.Background())
+
+	c = h.MeasureReadiness(context.Background())
+	assert.Equal(t, StatusSkipped, c.Results["postgres"].Status)
+
+	c, err = h.MeasureSelected(context.Background(), Selector{Names: []string{"postgres"}})
+	require.NoError(t, err)
+	assert.Equal(t, StatusSkipped, c.Results["postgres"].Status)
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// drainingReason is the reason of the unavailable status while the service is draining
+const drainingReason = "draining"
+
+// Drain marks the service as shutting down: readiness measurements and the summary of all the checks
+// report StatusUnavailable with the "draining" reason right away, without running the checks, so that
+// the load balancers stop routing the traffic to the service. Liveness and startup checks are not affected.
+func (h *Health) Drain() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.draining = true
+}
+
+// Draining reports whether the service is shutting down.
+func (h *Health) Draining() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.draining
+}
+
+// DrainAndShutdown drains the health, waits for the pre-stop delay for the failing readiness to be noticed
+// and shuts the server down gracefully with http.Server.Shutdown. The server is shut down right away
+// if the context is done during the delay.
+func (h *Health) DrainAndShutdown(ctx context.Context, srv *http.Server, delay time.Duration) error {
+	h.Drain()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	return srv.Shutdown(ctx)
+}
+
+// drains reports whether the measurements of the probe kind fail while the service is draining
+func drains(probe Probe) bool {
+	return probe == "" || probe == ProbeReadiness
+}
+
+// measureDraining returns the unavailable summary of the draining service without running the checks
+func (h *Health) measureDraining(ctx context.Context, sel Selector) Check {
+	c := Check{
+		Status:    StatusUnavailable,
+		Timestamp: time.Now(),
+		Reason:    drainingReason,
+	}
+
+	h.mu.Lock()
+	c.Component = h.component
+	// summary status of the subset of checks is not the status of the probe kind
+	if !sel.filtered() {
+		h.notifyStatus(sel.Probe, c.Status)
+	}
+	h.mu.Unlock()
+
+	if !sel.filtered() {
+		h.observeStatus(ctx, sel.Probe, c.Status)
+	}
+
+	return c
+}
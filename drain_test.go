@@ -0,0 +1,176 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_Drain(t *testing.T) {
+	var readiness, liveness atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			readiness.Add(1)
+			return nil
+		},
+	}, Config{
+		Name:   "ping",
+		Probes: []Probe{ProbeLiveness},
+		Check: func(context.Context) error {
+			liveness.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	assert.False(t, h.Draining())
+	assert.Equal(t, StatusOK, h.MeasureReadiness(context.Background()).Status)
+
+	h.Drain()
+	assert.True(t, h.Draining())
+
+	for _, c := range []Check{h.Measure(context.Background()), h.MeasureReadiness(context.Background())} {
+		assert.Equal(t, StatusUnavailable, c.Status)
+		assert.Equal(t, drainingReason, c.Reason)
+		assert.Empty(t, c.Results)
+	}
+	assert.Equal(t, int32(1), readiness.Load(), "checks must not run while draining")
+
+	c := h.MeasureLiveness(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Empty(t, c.Reason)
+	assert.Equal(t, int32(1), liveness.Load())
+
+	_, err = h.MeasureSelected(context.Background(), Selector{Probe: ProbeReadiness, Names: []string{"mysql"}})
+	var unknownErr *UnknownCheckError
+	assert.True(t, errors.As(err, &unknownErr))
+}
+
+func TestHealth_Drain_Handler(t *testing.T) {
+	h, err := New(
+		WithChecks(Config{
+			Name:  "postgres",
+			Check: func(context.Context) error { return nil },
+		}),
+		WithAuthorizer(BearerTokenAuthorizer("s3cr3t")),
+	)
+	require.NoError(t, err)
+
+	h.Drain()
+
+	res := httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "http://localhost/status", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+
+	var c Check
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &c))
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.Equal(t, drainingReason, c.Reason)
+
+	res = httptest.NewRecorder()
+	h.KubernetesHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "[-]draining\nreadyz check failed\n", res.Body.String())
+}
+
+func TestHealth_Drain_StatusListener(t *testing.T) {
+	events := make(chan [2]Status, 1)
+	h, err := New(WithStatusListener(func(probe Probe, prev, next Status) {
+		if probe == ProbeReadiness {
+			events <- [2]Status{prev, next}
+		}
+	}))
+	require.NoError(t, err)
+
+	h.Drain()
+	h.MeasureReadiness(context.Background())
+
+	select {
+	case e := <-events:
+		assert.Equal(t, [2]Status{StatusOK, StatusUnavailable}, e)
+	case <-time.After(time.Second):
+		t.Fatal("status listener was not notified")
+	}
+}
+
+func TestHealth_DrainAndShutdown(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: h.ReadinessHandler()}
+	go srv.Serve(lis)
+
+	addr := "http://" + lis.Addr().String()
+	res, err := http.Get(addr)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.DrainAndShutdown(context.Background(), srv, 200*time.Millisecond)
+	}()
+
+	// the server keeps serving the failing readiness during the pre-stop delay
+	require.Eventually(t, h.Draining, time.Second, time.Millisecond)
+	res, err = http.Get(addr)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("server was not shut down")
+	}
+
+	_, err = http.Get(addr)
+	assert.Error(t, err)
+}
+
+func TestHealth_DrainAndShutdown_ContextDone(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err = h.DrainAndShutdown(ctx, &http.Server{}, time.Minute)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, h.Draining())
+}
+
+func TestHealth_Drain_Formats(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	h.Drain()
+
+	for format, expected := range map[string]string{
+		"text":        "status: Unavailable\nreason: draining\n",
+		"health+json": `{"status":"fail","output":"draining"}`,
+	} {
+		res := httptest.NewRecorder()
+		h.HandlerFunc(res, httptest.NewRequest(http.MethodGet, "http://localhost/status?format="+url.QueryEscape(format), nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, res.Code, format)
+		assert.Equal(t, expected, res.Body.String(), format)
+	}
+}
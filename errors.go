@@ -0,0 +1,79 @@
+package health
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DegradedError is the error returned by a check that still works, but in a degraded way,
+// e.g. a slow replica or a nearly full disk. Measure reports such failures as StatusPartiallyAvailable
+// instead of StatusUnavailable, even if the check is not registered with SkipOnErr.
+type DegradedError struct {
+	Err error
+}
+
+// Degraded wraps the error into DegradedError, returns nil if err is nil.
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &DegradedError{Err: err}
+}
+
+// Error implements error interface.
+func (e *DegradedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *DegradedError) Unwrap() error {
+	return e.Err
+}
+
+// isDegraded reports whether the error is, or wraps, DegradedError
+func isDegraded(err error) bool {
+	var degraded *DegradedError
+	return errors.As(err, &degraded)
+}
+
+// UnknownCheckError is returned when the checks are selected by the names that are not registered.
+type UnknownCheckError struct {
+	// Unknown holds the names that are not registered.
+	Unknown []string
+	// Registered holds the names of all the registered checks.
+	Registered []string
+}
+
+// Error implements error interface.
+func (e *UnknownCheckError) Error() string {
+	return fmt.Sprintf(
+		"unknown health checks: %s; registered health checks: %s",
+		strings.Join(e.Unknown, ", "), strings.Join(e.Registered, ", "),
+	)
+}
+
+// PanicError is the failure of a check that panicked. Measure recovers the panics of the checks,
+// so that a broken check does not crash the whole process.
+type PanicError struct {
+	// Value is the value the check panicked with.
+	Value any
+	// Stack is the stack trace of the panicking goroutine, trimmed to the frames of the check.
+	Stack string
+}
+
+// Error implements error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("check panicked: %v", e.Value)
+}
+
+// asPanic returns the PanicError the error is, or wraps, nil if there is none
+func asPanic(err error) *PanicError {
+	var p *PanicError
+	if errors.As(err, &p) {
+		return p
+	}
+
+	return nil
+}
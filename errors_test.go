@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDegraded(t *testing.T) {
+	assert.NoError(t, Degraded(nil))
+
+	cause := errors.New("replication lag is 30s")
+	err := fmt.Errorf("replica check: %w", Degraded(cause))
+
+	assert.True(t, isDegraded(err))
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "replica check: replication lag is 30s", err.Error())
+	assert.False(t, isDegraded(cause))
+}
+
+func TestHealth_MeasureDegraded(t *testing.T) {
+	var hardErr error
+	h, err := New(WithChecks(Config{
+		Name:  "disk",
+		Check: func(context.Context) error { return Degraded(errors.New("disk is 95% full")) },
+	}, Config{
+		Name:      "uploads",
+		DependsOn: []string{"disk"},
+		Check:     func(context.Context) error { return nil },
+	}, Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return hardErr },
+	}))
+	require.NoError(t, err)
+
+	result := h.Measure(context.Background())
+	assert.Equal(t, StatusPartiallyAvailable, result.Status)
+	assert.Equal(t, map[string]string{"disk": "disk is 95% full"}, result.Failures)
+	assert.Equal(t, StatusPartiallyAvailable, result.Results["disk"].Status)
+	assert.False(t, result.Results["disk"].SkipOnErr)
+	assert.Equal(t, StatusOK, result.Results["uploads"].Status, "degraded dependency should not skip dependents")
+
+	hardErr = errors.New("connection refused")
+	result = h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, StatusUnavailable, result.Results["postgres"].Status)
+}
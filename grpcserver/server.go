@@ -0,0 +1,133 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const defaultWatchInterval = 5 * time.Second
+
+// Config is the gRPC health server configuration settings container.
+type Config struct {
+	// Services maps the gRPC service names to the checks that define their health, e.g. by check names or tags.
+	// The empty service name stands for the overall server health and maps to all the checks, unless set explicitly.
+	Services map[string]health.Selector
+	// WatchInterval is the period between two health evaluations of a watched service. The evaluation is shared
+	// by all the Watch streams of the service, so it does not depend on the number of the watchers.
+	// If not set or not positive - 5 seconds
+	WatchInterval time.Duration
+}
+
+// Server implements grpc_health_v1.HealthServer on top of health.Health.
+type Server struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	h      *health.Health
+	config Config
+
+	mu       sync.Mutex
+	watchers map[string]*watcher
+}
+
+// New creates new gRPC health server that reports the health of the services with the registered checks:
+// - SERVING when the checks summary status is health.StatusOK or health.StatusPartiallyAvailable
+// - NOT_SERVING when the checks summary status is health.StatusUnavailable
+func New(h *health.Health, config Config) *Server {
+	if config.WatchInterval <= 0 {
+		config.WatchInterval = defaultWatchInterval
+	}
+
+	services := make(map[string]health.Selector, len(config.Services)+1)
+	services[""] = health.Selector{}
+	for name, sel := range config.Services {
+		services[name] = sel
+	}
+	config.Services = services
+
+	return &Server{
+		h:        h,
+		config:   config,
+		watchers: make(map[string]*watcher),
+	}
+}
+
+// Check implements grpc_health_v1.HealthServer.
+func (s *Server) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	sel, ok := s.config.Services[req.GetService()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+
+	servingStatus, err := s.measure(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+	if servingStatus == grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "service %q has unregistered health checks", req.GetService())
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+// Watch implements grpc_health_v1.HealthServer. The current status is sent to the stream right away,
+// and then every time it changes.
+func (s *Server) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	sel, ok := s.config.Services[req.GetService()]
+	if !ok {
+		// unknown services are reported as such, as they may be registered later
+		if err := stream.Send(&grpc_health_v1.HealthCheckResponse{
+			Status: grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN,
+		}); err != nil {
+			return err
+		}
+
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	updates, unsubscribe := s.subscribe(req.GetService(), sel)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case u := <-updates:
+			if u.err != nil {
+				return u.err
+			}
+
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: u.status}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// measure evaluates the selected checks and maps the summary status to the serving one
+func (s *Server) measure(ctx context.Context, sel health.Selector) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	c, err := s.h.MeasureSelected(ctx, sel)
+	var unknownErr *health.UnknownCheckError
+	if errors.As(err, &unknownErr) {
+		// the checks of the service may be unregistered at runtime and registered again later
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, nil
+	}
+	if err != nil {
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN, status.Errorf(codes.Internal, "could not measure health: %v", err)
+	}
+
+	if c.Status == health.StatusUnavailable {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+	}
+
+	return grpc_health_v1.HealthCheckResponse_SERVING, nil
+}
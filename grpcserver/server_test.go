@@ -0,0 +1,199 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newClient(t *testing.T, srv *Server) grpc_health_v1.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestServer_Check(t *testing.T) {
+	h, err := health.New(health.WithChecks(health.Config{
+		Name:  "postgres",
+		Tags:  []string{"orders"},
+		Check: func(context.Context) error { return nil },
+	}, health.Config{
+		Name:  "redis",
+		Tags:  []string{"sessions"},
+		Check: func(context.Context) error { return errors.New("redis") },
+	}))
+	require.NoError(t, err)
+
+	client := newClient(t, New(h, Config{
+		Services: map[string]health.Selector{
+			"orders.v1.OrderService":     {Tags: []string{"orders"}},
+			"sessions.v1.SessionService": {Names: []string{"redis"}},
+			"broken.v1.BrokenService":    {Names: []string{"mysql"}},
+		},
+	}))
+
+	for service, expected := range map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{
+		"":                           grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		"orders.v1.OrderService":     grpc_health_v1.HealthCheckResponse_SERVING,
+		"sessions.v1.SessionService": grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+	} {
+		res, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		require.NoError(t, err, service)
+		assert.Equal(t, expected, res.GetStatus(), service)
+	}
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "broken.v1.BrokenService"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_Watch(t *testing.T) {
+	var failing atomic.Bool
+	h, err := health.New(health.WithChecks(health.Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			if failing.Load() {
+				return errors.New("postgres")
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	client := newClient(t, New(h, Config{WatchInterval: 10 * time.Millisecond}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+
+	failing.Store(true)
+	res, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, res.GetStatus())
+
+	failing.Store(false)
+	res, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+
+	unknown, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	require.NoError(t, err)
+
+	res, err = unknown.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, res.GetStatus())
+}
+
+func TestServer_Watch_Shared(t *testing.T) {
+	var calls atomic.Int32
+	h, err := health.New(health.WithChecks(health.Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	srv := New(h, Config{WatchInterval: time.Hour})
+	client := newClient(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for range 5 {
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+
+		res, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+	}
+
+	// the health is evaluated once for all the watchers of the service
+	assert.Equal(t, int32(1), calls.Load())
+
+	cancel()
+	require.Eventually(t, func() bool {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return len(srv.watchers) == 0
+	}, time.Second, 5*time.Millisecond, "watcher must stop once there are no streams")
+}
+
+func TestServer_Watch_Unregistered(t *testing.T) {
+	h, err := health.New(health.WithChecks(health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	srv := New(h, Config{
+		Services:      map[string]health.Selector{"orders.v1.OrderService": {Names: []string{"postgres"}}},
+		WatchInterval: -time.Second,
+	})
+	assert.Equal(t, defaultWatchInterval, srv.config.WatchInterval)
+	srv.config.WatchInterval = 10 * time.Millisecond
+
+	client := newClient(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "orders.v1.OrderService"})
+	require.NoError(t, err)
+
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+
+	// the stream stays open while the checks of the service are not registered
+	require.NoError(t, h.Unregister("postgres"))
+	res, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, res.GetStatus())
+
+	require.NoError(t, h.Register(health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}))
+	res, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+}
@@ -0,0 +1,105 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type (
+	// watcher evaluates the health of a service on behalf of all of its Watch streams,
+	// and pushes the status to them when it changes.
+	watcher struct {
+		subscribers map[chan update]struct{}
+		// last is the last evaluated status, nil until the first evaluation
+		last   *update
+		cancel context.CancelFunc
+	}
+
+	// update is the result of the service health evaluation.
+	update struct {
+		status grpc_health_v1.HealthCheckResponse_ServingStatus
+		err    error
+	}
+)
+
+// subscribe returns the channel receiving the status of the service when it changes, starting with
+// the current one, and the function to stop receiving it. The health of the service is evaluated
+// as long as there is at least one subscriber.
+func (s *Server) subscribe(service string, sel health.Selector) (<-chan update, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.watchers[service]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		w = &watcher{
+			subscribers: make(map[chan update]struct{}),
+			cancel:      cancel,
+		}
+		s.watchers[service] = w
+
+		go s.watch(ctx, w, sel)
+	}
+
+	// the channel keeps the latest update only, so that a slow stream does not stall the others
+	ch := make(chan update, 1)
+	w.subscribers[ch] = struct{}{}
+	if w.last != nil {
+		ch <- *w.last
+	}
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(w.subscribers, ch)
+		if len(w.subscribers) == 0 {
+			w.cancel()
+			delete(s.watchers, service)
+		}
+	}
+}
+
+// watch evaluates the health of the service every WatchInterval until the context is done
+func (s *Server) watch(ctx context.Context, w *watcher, sel health.Selector) {
+	ticker := time.NewTicker(s.config.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		servingStatus, err := s.measure(ctx, sel)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.publish(w, update{status: servingStatus, err: err})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish sends the update to the subscribers if the status has changed
+func (s *Server) publish(w *watcher, u update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w.last != nil && *w.last == u {
+		return
+	}
+	w.last = &u
+
+	for ch := range w.subscribers {
+		// replace the update that was not received yet with the latest one
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- u
+	}
+}
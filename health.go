@@ -2,12 +2,14 @@ package health
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -24,6 +26,18 @@ const (
 	StatusPartiallyAvailable Status = "Partially Available"
 	StatusUnavailable        Status = "Unavailable"
 	StatusTimeout            Status = "Timeout during health check"
+	StatusSkipped            Status = "Skipped"
+	StatusDisabled           Status = "Disabled"
+)
+
+// Probe type represents the kind of probe a check takes part in
+type Probe string
+
+// Possible probe kinds
+const (
+	ProbeLiveness  Probe = "liveness"
+	ProbeReadiness Probe = "readiness"
+	ProbeStartup   Probe = "startup"
 )
 
 type (
@@ -40,6 +54,68 @@ type (
 		SkipOnErr bool
 		// Check is the func which executes the check.
 		Check CheckFunc
+		// Probes lists the probe kinds the check belongs to.
+		// If empty, the check is part of the readiness probe only.
+		Probes []Probe
+		// DependsOn lists the names of the checks this check depends on. The check is executed after
+		// its dependencies and is skipped if any of them fails. The dependencies must be registered
+		// before the check, or be given to the same WithChecks.
+		DependsOn []string
+		// FailureThreshold is the number of consecutive failures after which the check is reported as failed.
+		// If not set - 1
+		FailureThreshold int
+		// SuccessThreshold is the number of consecutive successes after which the failed check
+		// is reported as passing again. If not set - 1
+		SuccessThreshold int
+		// Retry is the policy of retrying the failed check within its timeout.
+		// If not set - the check is executed once
+		Retry *RetryPolicy
+		// Tags are the free-form labels of the check, e.g. "critical", that can be used to select the checks.
+		Tags []string
+		// Interval is the period between two check runs when the checks run in the background (see Health.Start).
+		// If not set or not positive - 10 seconds
+		Interval time.Duration
+	}
+
+	// checkResult is the outcome of a single check execution.
+	checkResult struct {
+		name      string
+		skipOnErr bool
+		err       error
+		timedOut  bool
+		disabled  bool
+		skippedBy string
+		// tolerated is set for the failure that is not reported as the failure threshold is not reached yet
+		tolerated bool
+		startedAt time.Time
+		duration  time.Duration
+		attempts  int
+		// abandoned is closed once the execution that is still running after its timeout finishes
+		abandoned <-chan struct{}
+		// stuckFor is set for the execution that was not started because the previous one is still running
+		stuckFor time.Duration
+		// override is set for the result forced with OverrideCheck
+		override *Override
+	}
+
+	// checkState holds what is known about a check across executions.
+	checkState struct {
+		// last is the result of the last execution, nil if the check has not run yet
+		last          *checkResult
+		lastSuccess   time.Time
+		startupPassed bool
+		disabled      bool
+
+		// failing is set once the check reached its failure threshold, until it reaches its success threshold
+		failing              bool
+		consecutiveFailures  int
+		consecutiveSuccesses int
+		// panics is the number of the check executions that panicked
+		panics int
+		// stuck is the execution still running after its timeout, nil if there is none
+		stuck *stuckRun
+		// history holds the last results, it is created with the first result
+		history *history
 	}
 
 	// Check represents the health check response.
@@ -54,6 +130,47 @@ type (
 		*System `json:"system,omitempty"`
 		// Component holds information on the component for which checks are made
 		Component `json:"component"`
+		// Age is the age of the oldest cached result, set only when checks run in the background.
+		Age time.Duration `json:"age,omitempty"`
+		// Results holds the results of every evaluated check.
+		Results map[string]Result `json:"results,omitempty"`
+		// Reason explains the status that is not the outcome of the checks, e.g. when the service is draining.
+		Reason string `json:"reason,omitempty"`
+		// Override is set when the status is forced with OverrideStatus.
+		Override *Override `json:"override,omitempty"`
+	}
+
+	// Result represents the result of a single check.
+	Result struct {
+		// Status is the check status.
+		Status Status `json:"status"`
+		// Duration is the time the check took to complete.
+		Duration time.Duration `json:"duration"`
+		// StartedAt is the time in which the check started.
+		StartedAt time.Time `json:"started_at"`
+		// LastSuccess is the last time in which the check succeeded.
+		LastSuccess *time.Time `json:"last_success,omitempty"`
+		// Error is the failure message of the check.
+		Error string `json:"error,omitempty"`
+		// SkipOnErr is set when the check failed, but the failure did not make the whole service unavailable.
+		SkipOnErr bool `json:"skip_on_err,omitempty"`
+		// ConsecutiveFailures is the number of the check failures in a row.
+		ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+		// ConsecutiveSuccesses is the number of the check successes in a row.
+		ConsecutiveSuccesses int `json:"consecutive_successes,omitempty"`
+		// Attempts is the number of times the check was executed, including the retries.
+		Attempts int `json:"attempts,omitempty"`
+		// Stack is the trimmed stack trace of the check that panicked.
+		Stack string `json:"stack,omitempty"`
+		// Panics is the number of the check executions that panicked since the check was registered.
+		Panics int `json:"panics,omitempty"`
+		// Stuck is set when the check was not executed because its previous execution is still running
+		// after the timeout.
+		Stuck bool `json:"stuck,omitempty"`
+		// StuckFor is the time the stuck execution has been running for.
+		StuckFor time.Duration `json:"stuck_for,omitempty"`
+		// Override is set when the result is forced with OverrideCheck.
+		Override *Override `json:"override,omitempty"`
 	}
 
 	// System runtime variables about the go process.
@@ -76,6 +193,8 @@ type (
 		Name string `json:"name"`
 		// Version is the component version.
 		Version string `json:"version"`
+		// Notes holds free-form notes about the component, reported in application/health+json format.
+		Notes []string `json:"notes,omitempty"`
 	}
 
 	// Health is the health-checks container
@@ -83,6 +202,32 @@ type (
 		mu            sync.Mutex
 		checks        map[string]Config
 		maxConcurrent int
+		historySize   int
+
+		// states holds what is known about every registered check across executions
+		states map[string]*checkState
+		// flights holds the check evaluations in progress
+		flights map[string]*flight
+
+		observers []Observer
+
+		statusListeners      []StatusListener
+		checkStatusListeners []CheckStatusListener
+		// statuses holds the last summary status of every probe kind
+		statuses map[Probe]Status
+		notifier notifier
+
+		// started is set when the checks run in the background
+		started bool
+		cancels map[string]context.CancelFunc
+		sem     chan struct{}
+		wg      sync.WaitGroup
+
+		// draining is set once the service is shutting down
+		draining bool
+
+		statusOverride *Override
+		checkOverrides map[string]Override
 
 		tp                  trace.TracerProvider
 		instrumentationName string
@@ -90,15 +235,32 @@ type (
 		component Component
 
 		systemInfoEnabled bool
+
+		format             Format
+		statusCodes        map[Status]int
+		hideFailureDetails bool
+		authorizer         Authorizer
 	}
 )
 
 // New instantiates and build new health check container
 func New(opts ...Option) (*Health, error) {
 	h := &Health{
-		checks:        make(map[string]Config),
-		tp:            trace.NewNoopTracerProvider(),
-		maxConcurrent: runtime.NumCPU(),
+		checks:         make(map[string]Config),
+		states:         make(map[string]*checkState),
+		flights:        make(map[string]*flight),
+		statuses:       make(map[Probe]Status),
+		checkOverrides: make(map[string]Override),
+		cancels:        make(map[string]context.CancelFunc),
+		tp:             trace.NewNoopTracerProvider(),
+		maxConcurrent:  runtime.NumCPU(),
+		historySize:    defaultHistorySize,
+		format:         FormatJSON,
+		statusCodes: map[Status]int{
+			StatusOK:                 http.StatusOK,
+			StatusPartiallyAvailable: http.StatusOK,
+			StatusUnavailable:        http.StatusServiceUnavailable,
+		},
 	}
 
 	for _, o := range opts {
@@ -112,9 +274,7 @@ func New(opts ...Option) (*Health, error) {
 
 // Register registers a check config to be performed.
 func (h *Health) Register(c Config) error {
-	if c.Timeout == 0 {
-		c.Timeout = time.Second * 2
-	}
+	c = withDefaults(c)
 
 	if c.Name == "" {
 		return errors.New("health check must have a name to be registered")
@@ -127,11 +287,134 @@ func (h *Health) Register(c Config) error {
 		return fmt.Errorf("health check %q is already registered", c.Name)
 	}
 
+	if err := h.validateDependencies(c); err != nil {
+		return err
+	}
+
 	h.checks[c.Name] = c
+	h.states[c.Name] = &checkState{}
+	if h.started {
+		h.schedule(c)
+	}
+
+	return nil
+}
+
+// Unregister removes the registered check, so it is not performed anymore.
+// Checks that other registered checks depend on can not be unregistered.
+func (h *Health) Unregister(name string) error {
+	h.mu.Lock()
+
+	if _, ok := h.checks[name]; !ok {
+		h.mu.Unlock()
+		return fmt.Errorf("health check %q is not registered", name)
+	}
+
+	if dependents := h.dependents(name); len(dependents) > 0 {
+		h.mu.Unlock()
+		return fmt.Errorf("health check %q is a dependency of %s", name, strings.Join(dependents, ", "))
+	}
+
+	h.unschedule(name)
+	delete(h.checks, name)
+	delete(h.states, name)
+	delete(h.checkOverrides, name)
+	h.mu.Unlock()
+
+	h.observeUnregister(context.Background(), name)
+
+	return nil
+}
+
+// Replace replaces the registered check config with the one with the same name.
+// Results of the previous config are discarded, but the check stays disabled if it was.
+func (h *Health) Replace(c Config) error {
+	c = withDefaults(c)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[c.Name]; !ok {
+		return fmt.Errorf("health check %q is not registered", c.Name)
+	}
+
+	if err := h.validateDependencies(c); err != nil {
+		return err
+	}
+
+	prev := h.states[c.Name]
+
+	h.unschedule(c.Name)
+	h.checks[c.Name] = c
+	// the history of the check is kept, as it is still the same check
+	h.states[c.Name] = &checkState{disabled: prev.disabled, history: prev.history}
+	if h.started && !prev.disabled {
+		h.schedule(c)
+	}
+
+	return nil
+}
+
+// Disable stops performing the registered check until it is enabled again.
+// Disabled checks are reported with StatusDisabled and do not affect the summary status.
+func (h *Health) Disable(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.states[name]
+	if !ok {
+		return fmt.Errorf("health check %q is not registered", name)
+	}
+
+	state.disabled = true
+	h.unschedule(name)
+
+	return nil
+}
+
+// Enable resumes performing the registered check disabled with Disable.
+func (h *Health) Enable(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.states[name]
+	if !ok {
+		return fmt.Errorf("health check %q is not registered", name)
+	}
+
+	if !state.disabled {
+		return nil
+	}
+
+	state.disabled = false
+	if h.started {
+		h.schedule(h.checks[name])
+	}
 
 	return nil
 }
 
+// withDefaults returns the check config with the defaults set for the missing values
+func withDefaults(c Config) Config {
+	if c.Timeout == 0 {
+		c.Timeout = time.Second * 2
+	}
+
+	if c.Interval <= 0 {
+		c.Interval = defaultInterval
+	}
+
+	if c.FailureThreshold < 1 {
+		c.FailureThreshold = 1
+	}
+
+	if c.SuccessThreshold < 1 {
+		c.SuccessThreshold = 1
+	}
+
+	return c
+}
+
 // Handler returns an HTTP handler (http.HandlerFunc).
 func (h *Health) Handler() http.Handler {
 	return http.HandlerFunc(h.HandlerFunc)
@@ -139,115 +422,496 @@ func (h *Health) Handler() http.Handler {
 
 // HandlerFunc is the HTTP handler function.
 func (h *Health) HandlerFunc(w http.ResponseWriter, r *http.Request) {
-	c := h.Measure(r.Context())
+	h.serve(w, r, "")
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	data, err := json.Marshal(c)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+// LivenessHandler returns an HTTP handler that runs liveness checks only.
+func (h *Health) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, ProbeLiveness)
+	})
+}
 
-	code := http.StatusOK
-	if c.Status == StatusUnavailable {
-		code = http.StatusServiceUnavailable
-	}
-	w.WriteHeader(code)
-	w.Write(data)
+// ReadinessHandler returns an HTTP handler that runs readiness checks only.
+func (h *Health) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, ProbeReadiness)
+	})
+}
+
+// StartupHandler returns an HTTP handler that runs startup checks only.
+func (h *Health) StartupHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.serve(w, r, ProbeStartup)
+	})
 }
 
 // Measure runs all the registered health checks and returns summary status
 func (h *Health) Measure(ctx context.Context) Check {
+	c, _ := h.MeasureSelected(ctx, Selector{})
+	return c
+}
+
+// MeasureLiveness runs the registered liveness checks and returns summary status
+func (h *Health) MeasureLiveness(ctx context.Context) Check {
+	c, _ := h.MeasureSelected(ctx, Selector{Probe: ProbeLiveness})
+	return c
+}
+
+// MeasureReadiness runs the registered readiness checks and returns summary status.
+// Checks that do not declare any probe kind are treated as readiness checks.
+func (h *Health) MeasureReadiness(ctx context.Context) Check {
+	c, _ := h.MeasureSelected(ctx, Selector{Probe: ProbeReadiness})
+	return c
+}
+
+// MeasureStartup runs the registered startup checks and returns summary status.
+// Startup checks that have already passed once are not executed again, see MeasureSelected.
+func (h *Health) MeasureStartup(ctx context.Context) Check {
+	c, _ := h.MeasureSelected(ctx, Selector{Probe: ProbeStartup})
+	return c
+}
+
+// MeasureSelected runs the registered checks matching the selector and returns summary status.
+// Startup checks that have already passed once are not executed again and are reported with their last result,
+// except for the other probe kinds the checks belong to.
+// Returns UnknownCheckError if the selector refers to the checks that are not registered.
+func (h *Health) MeasureSelected(ctx context.Context, sel Selector) (Check, error) {
+	probe := sel.Probe
+
 	h.mu.Lock()
-	defer h.mu.Unlock()
+	plan, err := h.newPlan(sel)
+	draining := h.draining && drains(probe)
+	h.mu.Unlock()
+	if err != nil {
+		return Check{}, err
+	}
+
+	if draining {
+		return h.measureDraining(ctx, sel), nil
+	}
 
 	tracer := h.tp.Tracer(h.instrumentationName)
 
+	attrs := []attribute.KeyValue{attribute.Int("checks", len(plan.run))}
+	if probe != "" {
+		attrs = append(attrs, attribute.String("probe", string(probe)))
+	}
+
 	ctx, span := tracer.Start(
 		ctx,
 		"health.Measure",
-		trace.WithAttributes(attribute.Int("checks", len(h.checks))),
+		trace.WithAttributes(attrs...),
 	)
 	defer span.End()
 
-	status := StatusOK
-	failures := make(map[string]string)
+	// summary status of the subset of checks is not the status of the probe kind
+	report := !sel.filtered()
+
+	var results []checkResult
+	if !plan.started && len(plan.run) > 0 {
+		var coalesced bool
+		results, coalesced = h.runCoalesced(ctx, tracer, plan)
+		span.SetAttributes(attribute.Bool("coalesced", coalesced))
+		// the caller that gave up on the shared evaluation leaves reporting the status to the one running it
+		report = report && !(coalesced && ctx.Err() != nil)
+	}
+
+	h.mu.Lock()
+	if plan.started {
+		results = h.cachedResults(plan.run)
+	}
+	results = append(results, h.cachedResults(plan.passed)...)
+	for _, c := range plan.disabled {
+		results = append(results, checkResult{name: c.Name, disabled: true})
+	}
+
+	c := h.newCheck(h.overridden(results))
+	if o, ok := h.activeStatusOverride(probe); ok {
+		c.Status = o.Status
+		c.Reason = o.Reason
+		c.Override = &o
+	}
+	if report {
+		h.notifyStatus(probe, c.Status)
+	}
+	h.mu.Unlock()
+
+	span.SetAttributes(attribute.String("status", string(c.Status)))
+	if report {
+		h.observeStatus(ctx, probe, c.Status)
+	}
+
+	return c, nil
+}
+
+// runChecks executes the given checks concurrently, respecting the max concurrency limit
+// and the dependencies between the checks. Every result is passed through store as soon as
+// the check completes, so that dependents see the result as it is going to be reported.
+func (h *Health) runChecks(
+	ctx context.Context,
+	tracer trace.Tracer,
+	checks []Config,
+	store func(checkResult) checkResult,
+) []checkResult {
+	checks = sortByDependencies(checks)
+	results := make([]checkResult, len(checks))
+
+	doneCh := make(map[string]chan struct{}, len(checks))
+	index := make(map[string]int, len(checks))
+	for i, c := range checks {
+		doneCh[c.Name] = make(chan struct{})
+		index[c.Name] = i
+	}
 
 	limiterCh := make(chan bool, h.maxConcurrent)
 	defer close(limiterCh)
 
-	var (
-		wg sync.WaitGroup
-		mu sync.Mutex
-	)
-	for _, c := range h.checks {
+	var wg sync.WaitGroup
+	for i, c := range checks {
 		limiterCh <- true
 		wg.Add(1)
 
-		go func(c Config) {
-			ctx, span := tracer.Start(ctx, c.Name)
+		go func(i int, c Config) {
 			defer func() {
-				span.End()
+				close(doneCh[c.Name])
 				<-limiterCh
 				wg.Done()
 			}()
 
-			resCh := make(chan error, 1)
+			for _, dep := range c.DependsOn {
+				ch, ok := doneCh[dep]
+				if ok {
+					<-ch
+				}
 
-			go func() {
-				resCh <- c.Check(ctx)
-				defer close(resCh)
-			}()
+				// the dependency overridden with OverrideCheck is considered the way it is reported,
+				// and the one that is not selected to run is considered by its last result
+				h.mu.Lock()
+				var failed bool
+				if ok {
+					failed = h.overriddenResult(results[index[dep]]).failedHard()
+				} else {
+					failed = h.dependencyFailed(dep)
+				}
+				h.mu.Unlock()
 
-			timeout := time.NewTimer(c.Timeout)
+				if failed {
+					results[i] = store(skippedResult(c, dep))
+					return
+				}
+			}
 
-			select {
-			case <-timeout.C:
-				mu.Lock()
-				defer mu.Unlock()
+			h.mu.Lock()
+			stuck, ok := h.stuckResult(c)
+			h.mu.Unlock()
+			if ok {
+				results[i] = store(stuck)
+				return
+			}
 
-				span.SetStatus(codes.Error, string(StatusTimeout))
+			results[i] = store(runCheck(ctx, tracer, c))
+		}(i, c)
+	}
 
-				failures[c.Name] = string(StatusTimeout)
-				status = getAvailability(status, c.SkipOnErr)
-			case res := <-resCh:
-				if !timeout.Stop() {
-					<-timeout.C
-				}
+	wg.Wait()
 
-				mu.Lock()
-				defer mu.Unlock()
+	return results
+}
 
-				if res != nil {
-					span.RecordError(res)
+// runCheck executes a single check within its timeout, the check context is cancelled once the timeout is reached
+func runCheck(ctx context.Context, tracer trace.Tracer, c Config) checkResult {
+	ctx, span := tracer.Start(ctx, c.Name)
+	defer span.End()
 
-					failures[c.Name] = res.Error()
-					status = getAvailability(status, c.SkipOnErr)
-				}
-			}
-		}(c)
+	res := checkResult{
+		name:      c.Name,
+		skipOnErr: c.SkipOnErr,
+		startedAt: time.Now(),
 	}
 
-	wg.Wait()
-	span.SetAttributes(attribute.String("status", string(status)))
+	// the check is given the context cancelled at its timeout, so that it can give up in time
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	resCh := make(chan error, 1)
+	finished := make(chan struct{})
+
+	var attempts atomic.Int32
+	go func() {
+		defer close(finished)
+		resCh <- c.Retry.do(ctx, res.startedAt.Add(c.Timeout), recovered(c.Check), &attempts)
+		defer close(resCh)
+	}()
+
+	timeout := time.NewTimer(c.Timeout)
+
+	select {
+	case <-timeout.C:
+		span.SetStatus(codes.Error, string(StatusTimeout))
+
+		res.timedOut = true
+		select {
+		case <-finished:
+		default:
+			res.abandoned = finished
+		}
+	case err := <-resCh:
+		if !timeout.Stop() {
+			<-timeout.C
+		}
+
+		if errors.Is(err, context.DeadlineExceeded) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// the check gave up on its context deadline right before the timer fired
+			span.SetStatus(codes.Error, string(StatusTimeout))
+
+			res.timedOut = true
+			break
+		}
+
+		if p := asPanic(err); p != nil {
+			span.SetAttributes(attribute.Bool("panicked", true))
+			span.RecordError(err, trace.WithAttributes(attribute.String("exception.stacktrace", p.Stack)))
+			span.SetStatus(codes.Error, err.Error())
+		} else if err != nil {
+			span.RecordError(err)
+		}
+		res.err = err
+	}
+
+	res.duration = time.Since(res.startedAt)
+	res.attempts = int(attempts.Load())
+	span.SetAttributes(attribute.Int("attempts", res.attempts))
+
+	return res
+}
+
+// storeResult keeps the result of the check execution and returns it the way it is going to be reported,
+// taking into account the failure and success thresholds of the check. Must be called with h.mu held.
+// The result is dropped if the check was unregistered or replaced since its state was taken.
+func (h *Health) storeResult(state *checkState, res checkResult) checkResult {
+	c, ok := h.checks[res.name]
+	if !ok || h.states[res.name] != state {
+		return res
+	}
+
+	if res.abandoned != nil {
+		state.stuck = &stuckRun{since: res.startedAt, done: res.abandoned}
+	}
+
+	prev, prevFailed := StatusOK, false
+	if state.last != nil {
+		prev, prevFailed = state.last.status(), state.last.failed()
+	}
+	defer func() {
+		if state.last.failed() != prevFailed {
+			h.notifyCheckStatus(res.name, prev, state.last.status())
+		}
+	}()
+
+	switch {
+	case res.skippedBy != "":
+		// skipped checks were not executed, so they do not affect the streaks
+	case res.failed():
+		if asPanic(res.err) != nil {
+			state.panics++
+		}
+		state.consecutiveFailures++
+		state.consecutiveSuccesses = 0
+		if state.consecutiveFailures >= c.FailureThreshold {
+			state.failing = true
+		}
+		res.tolerated = !state.failing
+	default:
+		state.consecutiveSuccesses++
+		state.consecutiveFailures = 0
+		state.lastSuccess = res.startedAt.Add(res.duration)
+		if state.consecutiveSuccesses >= c.SuccessThreshold {
+			state.failing = false
+		}
+		if state.failing {
+			res.err = fmt.Errorf(
+				"check is recovering: %d of %d consecutive successes",
+				state.consecutiveSuccesses, c.SuccessThreshold,
+			)
+		}
+
+		if c.hasProbe(ProbeStartup) {
+			state.startupPassed = true
+		}
+	}
+
+	state.last = &res
+
+	if state.history == nil {
+		state.history = newHistory(h.historySize)
+	}
+	state.history.add(HistoryEntry{
+		Timestamp: res.startedAt,
+		Status:    res.status(),
+		Duration:  res.duration,
+		Error:     res.message(),
+	})
+
+	return res
+}
+
+// newCheck builds the summary from the check results, must be called with h.mu held
+func (h *Health) newCheck(results []checkResult) Check {
+	failures := make(map[string]string)
+	details := make(map[string]Result, len(results))
+	timestamp := time.Now()
+
+	for _, res := range results {
+		if res.failed() {
+			failures[res.name] = res.message()
+		}
+
+		details[res.name] = h.newResult(res)
+
+		if h.started && !res.startedAt.IsZero() && res.startedAt.Before(timestamp) {
+			timestamp = res.startedAt
+		}
+	}
 
 	var systemMetrics *System
 	if h.systemInfoEnabled {
 		systemMetrics = newSystemMetrics()
 	}
 
-	return newCheck(h.component, status, systemMetrics, failures)
+	c := Check{
+		Status:    summaryStatus(results),
+		Timestamp: timestamp,
+		Failures:  failures,
+		System:    systemMetrics,
+		Component: h.component,
+		Results:   details,
+	}
+	if h.started {
+		c.Age = time.Since(timestamp)
+	}
+
+	return c
 }
 
-func newCheck(c Component, s Status, system *System, failures map[string]string) Check {
-	return Check{
-		Status:    s,
-		Timestamp: time.Now(),
-		Failures:  failures,
-		System:    system,
-		Component: c,
+// summaryStatus returns the status of the service given the results of its checks
+func summaryStatus(results []checkResult) Status {
+	status := StatusOK
+	for _, res := range results {
+		if res.failed() {
+			status = getAvailability(status, res.skipOnErr || res.degraded())
+		}
+	}
+
+	return status
+}
+
+// newResult builds the public result of the check execution, must be called with h.mu held
+func (h *Health) newResult(res checkResult) Result {
+	r := Result{
+		Status:    StatusOK,
+		Duration:  res.duration,
+		StartedAt: res.startedAt,
+		Error:     res.message(),
+		Attempts:  res.attempts,
+	}
+
+	if state, ok := h.states[res.name]; ok {
+		if !state.lastSuccess.IsZero() {
+			lastSuccess := state.lastSuccess
+			r.LastSuccess = &lastSuccess
+		}
+
+		r.ConsecutiveFailures = state.consecutiveFailures
+		r.ConsecutiveSuccesses = state.consecutiveSuccesses
+		r.Panics = state.panics
+	}
+
+	if p := asPanic(res.err); p != nil {
+		r.Stack = p.Stack
+	}
+
+	if res.stuckFor > 0 {
+		r.Stuck = true
+		r.StuckFor = res.stuckFor
+	}
+
+	r.Override = res.override
+
+	r.Status = res.status()
+	r.SkipOnErr = res.failed() && res.skipOnErr
+
+	return r
+}
+
+// Executed reports whether the check was executed to produce the result. Skipped and disabled checks are not
+// executed, neither is the stuck check while its previous execution is still running.
+func (r Result) Executed() bool {
+	return !r.Stuck && r.Status != StatusSkipped && r.Status != StatusDisabled
+}
+
+// TimedOut reports whether the check execution timed out. The stuck check is reported as timed out once,
+// by the execution that timed out, not by the results that follow while it is still running.
+func (r Result) TimedOut() bool {
+	return r.Status == StatusTimeout && r.Executed()
+}
+
+// status returns the status of the check execution
+func (r checkResult) status() Status {
+	switch {
+	case r.tolerated:
+		// failure threshold is not reached yet
+		return StatusOK
+	case r.timedOut:
+		return StatusTimeout
+	case r.disabled:
+		return StatusDisabled
+	case r.skippedBy != "":
+		return StatusSkipped
+	case r.err != nil:
+		return getAvailability(StatusOK, r.skipOnErr || r.degraded())
+	}
+
+	return StatusOK
+}
+
+// failed reports whether the check execution did not succeed
+func (r checkResult) failed() bool {
+	return (r.timedOut || r.err != nil || r.skippedBy != "") && !r.tolerated
+}
+
+// degraded reports whether the check reported a degraded result rather than a hard failure
+func (r checkResult) degraded() bool {
+	return r.err != nil && isDegraded(r.err)
+}
+
+// failedHard reports whether the check failed with other than a degraded result
+func (r checkResult) failedHard() bool {
+	return r.failed() && !r.degraded()
+}
+
+// message returns the failure message of the check execution
+func (r checkResult) message() string {
+	if r.timedOut {
+		return string(StatusTimeout)
 	}
+	if r.skippedBy != "" {
+		return fmt.Sprintf("skipped due to %s", r.skippedBy)
+	}
+	if r.err != nil {
+		return r.err.Error()
+	}
+
+	return ""
+}
+
+// hasProbe reports whether the check belongs to the given probe kind
+func (c Config) hasProbe(p Probe) bool {
+	if len(c.Probes) == 0 {
+		return p == ProbeReadiness
+	}
+
+	return slices.Contains(c.Probes, p)
 }
 
 func newSystemMetrics() *System {
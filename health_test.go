@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -187,3 +188,382 @@ func TestHealth_CheckTimeoutGoroutineLeak(t *testing.T) {
 		t.Errorf("potential goroutine leak: before=%d after=%d", before, after)
 	}
 }
+
+func TestHealth_MeasureProbes(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:   "liveness",
+		Probes: []Probe{ProbeLiveness},
+		Check:  func(context.Context) error { return nil },
+	}, Config{
+		Name:  "readiness",
+		Check: func(context.Context) error { return errors.New("readiness") },
+	}, Config{
+		Name:   "both",
+		Probes: []Probe{ProbeLiveness, ProbeReadiness},
+		Check:  func(context.Context) error { return errors.New("both") },
+	}))
+	require.NoError(t, err)
+
+	result := h.MeasureLiveness(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, map[string]string{"both": "both"}, result.Failures)
+
+	result = h.MeasureReadiness(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, map[string]string{"readiness": "readiness", "both": "both"}, result.Failures)
+
+	result = h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Len(t, result.Failures, 2)
+
+	result = h.MeasureStartup(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Empty(t, result.Failures)
+}
+
+func TestHealth_MeasureStartupRunsUntilPassed(t *testing.T) {
+	var calls int
+	h, err := New(WithChecks(Config{
+		Name:   "migrations",
+		Probes: []Probe{ProbeStartup},
+		Check: func(context.Context) error {
+			calls++
+			if calls < 2 {
+				return errors.New("migrations are not applied yet")
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	result := h.MeasureStartup(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+
+	result = h.MeasureStartup(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+
+	result = h.MeasureStartup(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Equal(t, 2, calls, "startup check should not run once it has passed")
+}
+
+func TestHealth_MeasureStartupPassedAllProbes(t *testing.T) {
+	var migrations, cache atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name:   "migrations",
+		Probes: []Probe{ProbeStartup},
+		Check: func(context.Context) error {
+			migrations.Add(1)
+			return nil
+		},
+	}, Config{
+		Name:   "cache",
+		Probes: []Probe{ProbeStartup, ProbeReadiness},
+		Check: func(context.Context) error {
+			cache.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	for range 3 {
+		result := h.Measure(context.Background())
+		assert.Equal(t, StatusOK, result.Status)
+		assert.Equal(t, StatusOK, result.Results["migrations"].Status)
+	}
+	h.MeasureStartup(context.Background())
+	assert.Equal(t, int32(1), migrations.Load(), "passed startup check should not run again")
+
+	h.MeasureReadiness(context.Background())
+	assert.Equal(t, int32(4), cache.Load(), "startup check of the other probe kinds should keep running for them")
+}
+
+func TestHealth_ProbeHandlers(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:   "liveness",
+		Probes: []Probe{ProbeLiveness},
+		Check:  func(context.Context) error { return nil },
+	}, Config{
+		Name:  "readiness",
+		Check: func(context.Context) error { return errors.New("readiness") },
+	}))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		handler http.Handler
+		code    int
+	}{
+		{handler: h.LivenessHandler(), code: http.StatusOK},
+		{handler: h.ReadinessHandler(), code: http.StatusServiceUnavailable},
+		{handler: h.StartupHandler(), code: http.StatusOK},
+	} {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+		tc.handler.ServeHTTP(res, req)
+
+		assert.Equal(t, tc.code, res.Code)
+	}
+}
+
+func TestHealth_MeasureResults(t *testing.T) {
+	var fail bool
+	h, err := New(WithChecks(Config{
+		Name: "ok",
+		Check: func(context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		},
+	}, Config{
+		Name: "flaky",
+		Check: func(context.Context) error {
+			if fail {
+				return errors.New("flaky")
+			}
+			return nil
+		},
+	}, Config{
+		Name:      "optional",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New("optional") },
+	}, Config{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Check: func(context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	startedAt := time.Now()
+	result := h.Measure(context.Background())
+	require.Len(t, result.Results, 4)
+
+	ok := result.Results["ok"]
+	assert.Equal(t, StatusOK, ok.Status)
+	assert.GreaterOrEqual(t, ok.Duration, 10*time.Millisecond)
+	assert.False(t, ok.StartedAt.Before(startedAt))
+	require.NotNil(t, ok.LastSuccess)
+	assert.Empty(t, ok.Error)
+	assert.False(t, ok.SkipOnErr)
+
+	optional := result.Results["optional"]
+	assert.Equal(t, StatusPartiallyAvailable, optional.Status)
+	assert.Equal(t, "optional", optional.Error)
+	assert.True(t, optional.SkipOnErr)
+	assert.Nil(t, optional.LastSuccess)
+
+	slow := result.Results["slow"]
+	assert.Equal(t, StatusTimeout, slow.Status)
+	assert.Equal(t, string(StatusTimeout), slow.Error)
+
+	lastSuccess := result.Results["flaky"].LastSuccess
+	require.NotNil(t, lastSuccess)
+
+	fail = true
+	result = h.Measure(context.Background())
+
+	flaky := result.Results["flaky"]
+	assert.Equal(t, StatusUnavailable, flaky.Status)
+	assert.Equal(t, "flaky", flaky.Error)
+	require.NotNil(t, flaky.LastSuccess)
+	assert.Equal(t, *lastSuccess, *flaky.LastSuccess)
+}
+
+func TestHealthHandlerResults(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "mongodb",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	h.HandlerFunc(res, req)
+
+	var body Check
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.NoError(t, err)
+
+	require.Contains(t, body.Results, "mongodb")
+	assert.Equal(t, StatusOK, body.Results["mongodb"].Status)
+	assert.NotNil(t, body.Results["mongodb"].LastSuccess)
+}
+
+func TestHealth_Unregister(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "tenant-db",
+		Check: func(context.Context) error { return errors.New("tenant-db") },
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusUnavailable, h.Measure(context.Background()).Status)
+
+	require.NoError(t, h.Unregister("tenant-db"))
+	require.Error(t, h.Unregister("tenant-db"))
+
+	result := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Empty(t, result.Results)
+
+	err = h.Register(Config{
+		Name:  "tenant-db",
+		Check: func(context.Context) error { return nil },
+	})
+	require.NoError(t, err)
+}
+
+func TestHealth_Replace(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return errors.New("old dsn") },
+	}, Config{
+		Name:      "replica",
+		DependsOn: []string{"postgres"},
+		Check:     func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	err = h.Replace(Config{
+		Name:  "unknown",
+		Check: func(context.Context) error { return nil },
+	})
+	require.Error(t, err)
+
+	err = h.Replace(Config{
+		Name:      "postgres",
+		DependsOn: []string{"replica"},
+		Check:     func(context.Context) error { return nil },
+	})
+	require.Error(t, err, "replacement introducing a dependency cycle should be rejected")
+
+	assert.Equal(t, StatusUnavailable, h.Measure(context.Background()).Status)
+
+	err = h.Replace(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	})
+	require.NoError(t, err)
+
+	result := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Empty(t, result.Failures)
+}
+
+func TestHealth_DisableEnable(t *testing.T) {
+	var calls int
+	h, err := New(WithChecks(Config{
+		Name: "rabbitmq",
+		Check: func(context.Context) error {
+			calls++
+			return errors.New("rabbitmq")
+		},
+	}))
+	require.NoError(t, err)
+
+	require.Error(t, h.Disable("unknown"))
+	require.Error(t, h.Enable("unknown"))
+
+	require.NoError(t, h.Disable("rabbitmq"))
+
+	result := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Empty(t, result.Failures)
+	assert.Equal(t, StatusDisabled, result.Results["rabbitmq"].Status)
+	assert.Equal(t, 0, calls)
+
+	require.NoError(t, h.Enable("rabbitmq"))
+
+	result = h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, 1, calls)
+}
+
+func TestHealth_MeasureThresholds(t *testing.T) {
+	var fail bool
+	h, err := New(WithChecks(Config{
+		Name:             "redis",
+		FailureThreshold: 3,
+		SuccessThreshold: 2,
+		Check: func(context.Context) error {
+			if fail {
+				return errors.New("i/o timeout")
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	fail = true
+	for i := 1; i < 3; i++ {
+		result := h.Measure(context.Background())
+		assert.Equal(t, StatusOK, result.Status, "failure %d should be tolerated", i)
+		assert.Empty(t, result.Failures)
+		assert.Equal(t, StatusOK, result.Results["redis"].Status)
+		assert.Equal(t, "i/o timeout", result.Results["redis"].Error)
+		assert.Equal(t, i, result.Results["redis"].ConsecutiveFailures)
+	}
+
+	result := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, "i/o timeout", result.Failures["redis"])
+	assert.Equal(t, 3, result.Results["redis"].ConsecutiveFailures)
+
+	fail = false
+	result = h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, "check is recovering: 1 of 2 consecutive successes", result.Failures["redis"])
+	assert.Equal(t, 0, result.Results["redis"].ConsecutiveFailures)
+	assert.Equal(t, 1, result.Results["redis"].ConsecutiveSuccesses)
+
+	result = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Empty(t, result.Failures)
+	assert.Equal(t, 2, result.Results["redis"].ConsecutiveSuccesses)
+
+	fail = true
+	result = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, result.Status, "streak should start over after recovery")
+}
+
+func TestResult_Executed(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	h, err := New(WithChecks(Config{
+		Name:    "stuck",
+		Timeout: 10 * time.Millisecond,
+		Check: func(context.Context) error {
+			<-release
+			return nil
+		},
+	}, Config{
+		Name:  "network",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:      "postgres",
+		DependsOn: []string{"network"},
+		Check:     func(context.Context) error { return nil },
+	}, Config{
+		Name:  "redis",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+	require.NoError(t, h.Disable("redis"))
+
+	c := h.Measure(context.Background())
+	assert.True(t, c.Results["stuck"].Executed())
+	assert.True(t, c.Results["stuck"].TimedOut())
+	assert.True(t, c.Results["network"].Executed())
+	assert.False(t, c.Results["network"].TimedOut())
+	assert.False(t, c.Results["postgres"].Executed(), "skipped check is not executed")
+	assert.False(t, c.Results["redis"].Executed(), "disabled check is not executed")
+
+	time.Sleep(20 * time.Millisecond)
+
+	c = h.Measure(context.Background())
+	require.True(t, c.Results["stuck"].Stuck)
+	assert.Equal(t, StatusTimeout, c.Results["stuck"].Status)
+	assert.False(t, c.Results["stuck"].Executed(), "stuck check is not executed again")
+	assert.False(t, c.Results["stuck"].TimedOut(), "stuck check timeout is reported once")
+}
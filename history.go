@@ -0,0 +1,184 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultHistorySize is the number of the results kept in the history of every check
+const defaultHistorySize = 100
+
+type (
+	// HistoryEntry is the result of a single check execution kept in the check history.
+	HistoryEntry struct {
+		// Timestamp is the time in which the check started.
+		Timestamp time.Time `json:"timestamp"`
+		// Status is the check status, the way it was reported.
+		Status Status `json:"status"`
+		// Duration is the time the check took to complete.
+		Duration time.Duration `json:"duration"`
+		// Error is the failure message of the check.
+		Error string `json:"error,omitempty"`
+	}
+
+	// history is the ring buffer of the last results of a check.
+	history struct {
+		entries []HistoryEntry
+		// start is the index of the oldest entry once the buffer is full
+		start int
+		size  int
+	}
+)
+
+// newHistory creates the history keeping up to size entries, nil if the history is disabled
+func newHistory(size int) *history {
+	if size <= 0 {
+		return nil
+	}
+
+	return &history{size: size}
+}
+
+// add appends the entry, overwriting the oldest one if the history is full
+func (hs *history) add(e HistoryEntry) {
+	if hs == nil {
+		return
+	}
+
+	if len(hs.entries) < hs.size {
+		hs.entries = append(hs.entries, e)
+		return
+	}
+
+	hs.entries[hs.start] = e
+	hs.start = (hs.start + 1) % hs.size
+}
+
+// between returns the entries in chronological order that started within the time range,
+// zero from or to leaves the range open on that side
+func (hs *history) between(from, to time.Time) []HistoryEntry {
+	if hs == nil {
+		return nil
+	}
+
+	var res []HistoryEntry
+	for i := range hs.entries {
+		e := hs.entries[(hs.start+i)%len(hs.entries)]
+		if (!from.IsZero() && e.Timestamp.Before(from)) || (!to.IsZero() && e.Timestamp.After(to)) {
+			continue
+		}
+
+		res = append(res, e)
+	}
+
+	return res
+}
+
+// History returns the results of the check kept in its history that started within the time range,
+// oldest first. Zero from or to leaves the range open on that side.
+func (h *Health) History(name string, from, to time.Time) ([]HistoryEntry, error) {
+	histories, err := h.HistorySelected(Selector{Names: []string{name}}, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return histories[name], nil
+}
+
+// HistorySelected returns the results kept in the history of the checks that match the selector
+// and started within the time range, by check name. Zero from or to leaves the range open on that side.
+func (h *Health) HistorySelected(sel Selector, from, to time.Time) (map[string][]HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.validateSelector(sel); err != nil {
+		return nil, err
+	}
+
+	histories := make(map[string][]HistoryEntry)
+	for _, c := range h.checks {
+		if !sel.matches(c) {
+			continue
+		}
+
+		if entries := h.states[c.Name].history.between(from, to); len(entries) > 0 {
+			histories[c.Name] = entries
+		}
+	}
+
+	return histories, nil
+}
+
+// HistoryHandler returns an HTTP handler that responds with the history of the checks as JSON, by check name.
+// The checks are selected with check, exclude and tag query parameters the same way as with Handler,
+// and the time range with from and to query parameters in RFC 3339 format, or with since query parameter
+// as a duration, e.g. "15m". The requests not approved by the authorizer set with WithAuthorizer are forbidden.
+func (h *Health) HistoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.authorized(r) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		query := r.URL.Query()
+		from, to, err := historyRange(query.Get("from"), query.Get("to"), query.Get("since"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		histories, err := h.HistorySelected(Selector{
+			Names:   query["check"],
+			Exclude: query["exclude"],
+			Tags:    query["tag"],
+		}, from, to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if h.hideFailureDetails {
+			for _, entries := range histories {
+				for i := range entries {
+					entries[i].Error = ""
+				}
+			}
+		}
+
+		data, err := json.Marshal(histories)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// historyRange parses the time range of the history request
+func historyRange(fromParam, toParam, sinceParam string) (from, to time.Time, err error) {
+	if fromParam != "" {
+		if from, err = time.Parse(time.RFC3339, fromParam); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+
+	if toParam != "" {
+		if to, err = time.Parse(time.RFC3339, toParam); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+
+	if sinceParam != "" {
+		since, err := time.ParseDuration(sinceParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since: %w", err)
+		}
+		from = time.Now().Add(-since)
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,210 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Now()
+	at := func(i int) time.Time {
+		return start.Add(time.Duration(i) * time.Second)
+	}
+
+	hs := newHistory(3)
+	assert.Empty(t, hs.between(time.Time{}, time.Time{}))
+
+	for i := range 5 {
+		hs.add(HistoryEntry{Timestamp: at(i)})
+	}
+
+	timestamps := func(entries []HistoryEntry) []time.Time {
+		res := make([]time.Time, 0, len(entries))
+		for _, e := range entries {
+			res = append(res, e.Timestamp)
+		}
+		return res
+	}
+
+	assert.Equal(t, []time.Time{at(2), at(3), at(4)}, timestamps(hs.between(time.Time{}, time.Time{})))
+	assert.Equal(t, []time.Time{at(3), at(4)}, timestamps(hs.between(at(3), time.Time{})))
+	assert.Equal(t, []time.Time{at(2), at(3)}, timestamps(hs.between(time.Time{}, at(3))))
+	assert.Equal(t, []time.Time{at(3)}, timestamps(hs.between(at(3), at(3))))
+
+	var disabled *history
+	disabled.add(HistoryEntry{})
+	assert.Empty(t, disabled.between(time.Time{}, time.Time{}))
+	assert.Nil(t, newHistory(0))
+}
+
+func TestHealth_History(t *testing.T) {
+	var calls atomic.Int32
+	h, err := New(
+		WithHistorySize(3),
+		WithChecks(Config{
+			Name: "postgres",
+			Check: func(context.Context) error {
+				if calls.Add(1)%2 == 0 {
+					return errors.New(checkErr)
+				}
+				return nil
+			},
+		}, Config{
+			Name:  "redis",
+			Tags:  []string{"cache"},
+			Check: func(context.Context) error { return nil },
+		}),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+	for range 4 {
+		h.Measure(context.Background())
+	}
+
+	entries, err := h.History("postgres", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []Status{StatusUnavailable, StatusOK, StatusUnavailable}, []Status{
+		entries[0].Status, entries[1].Status, entries[2].Status,
+	})
+	assert.Equal(t, checkErr, entries[0].Error)
+	assert.Empty(t, entries[1].Error)
+	assert.True(t, entries[0].Timestamp.After(start))
+	assert.True(t, entries[1].Timestamp.After(entries[0].Timestamp))
+
+	entries, err = h.History("postgres", time.Now(), time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = h.History("mysql", time.Time{}, time.Time{})
+	var unknownErr *UnknownCheckError
+	assert.True(t, errors.As(err, &unknownErr))
+
+	histories, err := h.HistorySelected(Selector{Tags: []string{"cache"}}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, histories, 1)
+	assert.Len(t, histories["redis"], 3)
+
+	// the history is kept when the check is replaced
+	require.NoError(t, h.Replace(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}))
+	h.Measure(context.Background())
+
+	entries, err = h.History("postgres", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, StatusOK, entries[2].Status)
+
+	require.NoError(t, h.Unregister("redis"))
+	histories, err = h.HistorySelected(Selector{}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, histories, 1)
+}
+
+func TestHealth_History_Disabled(t *testing.T) {
+	h, err := New(
+		WithHistorySize(0),
+		WithChecks(Config{
+			Name:  "postgres",
+			Check: func(context.Context) error { return nil },
+		}),
+	)
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+
+	entries, err := h.History("postgres", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestHealth_HistoryHandler(t *testing.T) {
+	h, err := New(
+		WithChecks(Config{
+			Name:  "postgres",
+			Check: func(context.Context) error { return errors.New(checkErr) },
+		}, Config{
+			Name:  "redis",
+			Check: func(context.Context) error { return nil },
+		}),
+		WithAuthorizer(BearerTokenAuthorizer("s3cr3t")),
+	)
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	handler := h.HistoryHandler()
+	do := func(query url.Values, authorized bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/history?"+query.Encode(), nil)
+		if authorized {
+			req.Header.Set("Authorization", "Bearer s3cr3t")
+		}
+
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		return res
+	}
+
+	assert.Equal(t, http.StatusForbidden, do(nil, false).Code)
+
+	res := do(url.Values{"check": {"postgres"}, "since": {"1m"}}, true)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+
+	var histories map[string][]HistoryEntry
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &histories))
+	require.Len(t, histories, 1)
+	require.Len(t, histories["postgres"], 2)
+	assert.Equal(t, StatusUnavailable, histories["postgres"][0].Status)
+	assert.Equal(t, checkErr, histories["postgres"][0].Error)
+
+	res = do(url.Values{"to": {time.Now().Add(-time.Minute).Format(time.RFC3339)}}, true)
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{}`, res.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, do(url.Values{"since": {"yesterday"}}, true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(url.Values{"from": {"yesterday"}}, true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(url.Values{"check": {"mysql"}}, true).Code)
+}
+
+func TestHealth_HistoryHandler_WithoutFailureDetails(t *testing.T) {
+	h, err := New(
+		WithChecks(Config{
+			Name:  "postgres",
+			Check: func(context.Context) error { return errors.New(checkErr) },
+		}),
+		WithFailureDetails(false),
+	)
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+
+	res := httptest.NewRecorder()
+	h.HistoryHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "http://localhost/history", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+
+	var histories map[string][]HistoryEntry
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &histories))
+	require.Len(t, histories["postgres"], 1)
+	assert.Empty(t, histories["postgres"][0].Error)
+
+	// the history kept in memory is not affected
+	entries, err := h.History("postgres", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, checkErr, entries[0].Error)
+}
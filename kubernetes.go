@@ -0,0 +1,144 @@
+package health
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// kubernetesEndpoints maps the Kubernetes-style endpoints to the probe kinds they evaluate
+var kubernetesEndpoints = map[string]Probe{
+	"livez":   ProbeLiveness,
+	"readyz":  ProbeReadiness,
+	"healthz": "",
+}
+
+// KubernetesHandler returns an HTTP handler that serves the health endpoints the way Kubernetes components do:
+// - /livez runs liveness checks
+// - /readyz runs readiness checks
+// - /healthz runs all the checks
+// - /livez/<name>, /readyz/<name> and /healthz/<name> run a single check of the endpoint
+//
+// The response is "ok" for the passing checks, or the per-check report like "[+]postgres ok" and
+// "[-]redis failed: <error>" for the failing ones and for the requests with verbose query parameter.
+// The checks can be excluded with the exclude query parameter. The single check endpoints are forbidden
+// to the requests not approved by the authorizer set with WithAuthorizer.
+func (h *Health) KubernetesHandler() http.Handler {
+	mux := http.NewServeMux()
+	for endpoint, probe := range kubernetesEndpoints {
+		mux.HandleFunc("GET /"+endpoint, func(w http.ResponseWriter, r *http.Request) {
+			h.serveKubernetes(w, r, endpoint, Selector{Probe: probe, Exclude: r.URL.Query()["exclude"]})
+		})
+		mux.HandleFunc("GET /"+endpoint+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+			h.serveKubernetes(w, r, endpoint, Selector{Probe: probe, Names: []string{r.PathValue("name")}})
+		})
+	}
+
+	return mux
+}
+
+// serveKubernetes measures the selected checks and writes the summary in Kubernetes health endpoints format
+func (h *Health) serveKubernetes(w http.ResponseWriter, r *http.Request, endpoint string, sel Selector) {
+	authorized := h.authorized(r)
+	if len(sel.Names) > 0 && !authorized {
+		// the single check endpoints would disclose the registered checks to the requests that are not allowed
+		// to see them, so they are forbidden the same way whether the check exists or not
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	if len(sel.Names) > 0 && !h.selectsAny(sel) {
+		// the check is not registered or does not belong to the probe kind of the endpoint,
+		// which is resolved before measuring as the draining endpoints report no checks at all
+		http.NotFound(w, r)
+		return
+	}
+
+	c, err := h.MeasureSelected(r.Context(), sel)
+
+	var unknownErr *UnknownCheckError
+	switch {
+	case errors.As(err, &unknownErr) && len(sel.Names) > 0:
+		// the check was unregistered meanwhile
+		http.NotFound(w, r)
+		return
+	case err != nil:
+		// do not disclose the registered checks to the requests that are not allowed to see them
+		if !authorized {
+			err = errors.New("unknown health checks")
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	code := h.statusCode(c)
+	if !authorized {
+		c = minimal(c)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+
+	_, verbose := r.URL.Query()["verbose"]
+	w.Write(h.renderKubernetes(c, endpoint, sel.Exclude, verbose))
+}
+
+// selectsAny reports whether the selector matches any of the registered checks
+func (h *Health) selectsAny(sel Selector) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, c := range h.checks {
+		if sel.matches(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderKubernetes renders the check summary in Kubernetes health endpoints format
+func (h *Health) renderKubernetes(c Check, endpoint string, excluded []string, verbose bool) []byte {
+	passed := c.Status != StatusUnavailable
+	if passed && !verbose {
+		return []byte("ok")
+	}
+
+	var buf bytes.Buffer
+	for _, name := range slices.Sorted(maps.Keys(c.Results)) {
+		r := c.Results[name]
+		switch {
+		case r.Status == StatusOK:
+			fmt.Fprintf(&buf, "[+]%s ok\n", name)
+		case r.Status == StatusDisabled:
+			fmt.Fprintf(&buf, "[+]%s disabled\n", name)
+		case h.hideFailureDetails || r.Error == "":
+			fmt.Fprintf(&buf, "[-]%s failed: reason withheld\n", name)
+		default:
+			fmt.Fprintf(&buf, "[-]%s failed: %s\n", name, r.Error)
+		}
+	}
+	switch {
+	case c.Reason == "":
+	case passed:
+		fmt.Fprintf(&buf, "[+]%s\n", c.Reason)
+	default:
+		fmt.Fprintf(&buf, "[-]%s\n", c.Reason)
+	}
+	if len(c.Results) > 0 {
+		for _, name := range slices.Sorted(slices.Values(excluded)) {
+			fmt.Fprintf(&buf, "[+]%s excluded: ok\n", name)
+		}
+	}
+
+	if passed {
+		fmt.Fprintf(&buf, "%s check passed\n", endpoint)
+	} else {
+		fmt.Fprintf(&buf, "%s check failed\n", endpoint)
+	}
+
+	return buf.Bytes()
+}
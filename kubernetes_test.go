@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_KubernetesHandler(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:   "ping",
+		Probes: []Probe{ProbeLiveness},
+		Check:  func(context.Context) error { return nil },
+	}, Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:  "redis",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	handler := h.KubernetesHandler()
+
+	for target, expected := range map[string]struct {
+		code int
+		body string
+	}{
+		"/livez":                        {http.StatusOK, "ok"},
+		"/livez?verbose":                {http.StatusOK, "[+]ping ok\nlivez check passed\n"},
+		"/livez/ping":                   {http.StatusOK, "ok"},
+		"/readyz":                       {http.StatusServiceUnavailable, "[+]postgres ok\n[-]redis failed: " + checkErr + "\nreadyz check failed\n"},
+		"/readyz?exclude=redis":         {http.StatusOK, "ok"},
+		"/readyz?exclude=redis&verbose": {http.StatusOK, "[+]postgres ok\n[+]redis excluded: ok\nreadyz check passed\n"},
+		"/readyz/postgres":              {http.StatusOK, "ok"},
+		"/readyz/redis":                 {http.StatusServiceUnavailable, "[-]redis failed: " + checkErr + "\nreadyz check failed\n"},
+		"/healthz?verbose":              {http.StatusServiceUnavailable, "[+]ping ok\n[+]postgres ok\n[-]redis failed: " + checkErr + "\nhealthz check failed\n"},
+		"/readyz/ping":                  {http.StatusNotFound, "404 page not found\n"},
+		"/readyz/mysql":                 {http.StatusNotFound, "404 page not found\n"},
+		"/readyz?exclude=mysql":         {http.StatusBadRequest, "unknown health checks: mysql; registered health checks: ping, postgres, redis\n"},
+	} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, target, nil))
+
+		assert.Equal(t, expected.code, res.Code, target)
+		assert.Equal(t, expected.body, res.Body.String(), target)
+		if res.Code != http.StatusNotFound && res.Code != http.StatusBadRequest {
+			assert.Equal(t, "text/plain; charset=utf-8", res.Header().Get("Content-Type"), target)
+		}
+	}
+
+	// the override reason that reads like the drain mode does not change how the checks are resolved
+	require.NoError(t, h.OverrideStatus(Override{Status: StatusUnavailable, Reason: drainingReason}))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readyz/ping", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	h.ClearStatusOverride()
+
+	h.Drain()
+
+	for target, expected := range map[string]struct {
+		code int
+		body string
+	}{
+		"/livez":           {http.StatusOK, "ok"},
+		"/readyz":          {http.StatusServiceUnavailable, "[-]draining\nreadyz check failed\n"},
+		"/readyz/postgres": {http.StatusServiceUnavailable, "[-]draining\nreadyz check failed\n"},
+		"/readyz/ping":     {http.StatusNotFound, "404 page not found\n"},
+		"/readyz/mysql":    {http.StatusNotFound, "404 page not found\n"},
+		"/livez/ping":      {http.StatusOK, "ok"},
+	} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, target, nil))
+
+		assert.Equal(t, expected.code, res.Code, "draining "+target)
+		assert.Equal(t, expected.body, res.Body.String(), "draining "+target)
+	}
+}
+
+func TestHealth_KubernetesHandler_FailureDetails(t *testing.T) {
+	h, err := New(
+		WithChecks(Config{
+			Name:  "redis",
+			Check: func(context.Context) error { return errors.New(checkErr) },
+		}),
+		WithFailureDetails(false),
+		WithAuthorizer(BearerTokenAuthorizer("s3cr3t")),
+	)
+	require.NoError(t, err)
+
+	handler := h.KubernetesHandler()
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readyz?verbose", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "readyz check failed\n", res.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz?verbose", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "[-]redis failed: reason withheld\nreadyz check failed\n", res.Body.String())
+
+	// unauthorized requests can not tell the registered checks from the unknown ones
+	for _, target := range []string{"/readyz/redis", "/readyz/mysql"} {
+		res = httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusForbidden, res.Code, target)
+		assert.Equal(t, "Forbidden\n", res.Body.String(), target)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/readyz/mysql", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
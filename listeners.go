@@ -0,0 +1,75 @@
+package health
+
+import "sync"
+
+type (
+	// StatusListener is notified when the summary status of the probe kind changes.
+	// Probe is empty for the summary status of all the checks.
+	StatusListener func(probe Probe, prev, next Status)
+
+	// CheckStatusListener is notified when the check starts or stops failing.
+	CheckStatusListener func(name string, prev, next Status)
+
+	// notifier delivers the notifications in order in a separate goroutine,
+	// so that slow listeners do not stall the checks.
+	notifier struct {
+		mu      sync.Mutex
+		queue   []func()
+		running bool
+	}
+)
+
+// notifyStatus notifies the status listeners if the summary status of the probe kind has changed.
+// The status is considered to be StatusOK before the first measurement. Must be called with h.mu held.
+func (h *Health) notifyStatus(probe Probe, next Status) {
+	prev, ok := h.statuses[probe]
+	if !ok {
+		prev = StatusOK
+	}
+	h.statuses[probe] = next
+
+	if prev == next {
+		return
+	}
+
+	for _, l := range h.statusListeners {
+		h.notifier.notify(func() { l(probe, prev, next) })
+	}
+}
+
+// notifyCheckStatus notifies the check status listeners, must be called with h.mu held
+func (h *Health) notifyCheckStatus(name string, prev, next Status) {
+	for _, l := range h.checkStatusListeners {
+		h.notifier.notify(func() { l(name, prev, next) })
+	}
+}
+
+// notify queues the notification and starts delivering the queue if it is not being delivered yet
+func (n *notifier) notify(f func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.queue = append(n.queue, f)
+	if !n.running {
+		n.running = true
+		go n.deliver()
+	}
+}
+
+// deliver calls the queued notifications until the queue is empty
+func (n *notifier) deliver() {
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 {
+			n.running = false
+			n.mu.Unlock()
+			return
+		}
+
+		f := n.queue[0]
+		n.queue = n.queue[1:]
+		n.mu.Unlock()
+
+		f()
+	}
+}
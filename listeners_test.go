@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.events...)
+}
+
+func TestHealth_StatusListeners(t *testing.T) {
+	var (
+		fail    bool
+		rec     recorder
+		release = make(chan struct{})
+	)
+
+	h, err := New(WithChecks(Config{
+		Name: "redis",
+		Check: func(context.Context) error {
+			if fail {
+				return errors.New("redis")
+			}
+			return nil
+		},
+	}), WithStatusListener(func(probe Probe, prev, next Status) {
+		// slow listener must not stall the checks
+		<-release
+		rec.record("status %q: %s -> %s", probe, prev, next)
+	}), WithCheckStatusListener(func(name string, prev, next Status) {
+		rec.record("check %s: %s -> %s", name, prev, next)
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+
+	fail = true
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	fail = false
+	h.MeasureReadiness(context.Background())
+	h.Measure(context.Background())
+
+	close(release)
+
+	require.Eventually(t, func() bool { return len(rec.get()) == 4 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{
+		`check redis: OK -> Unavailable`,
+		`status "": OK -> Unavailable`,
+		`check redis: Unavailable -> OK`,
+		`status "": Unavailable -> OK`,
+	}, rec.get())
+}
@@ -0,0 +1,165 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// logInterval is the minimal period between two log records of the same check failing persistently
+const logInterval = time.Minute
+
+type (
+	// logObserver logs the check failures and the status transitions.
+	logObserver struct {
+		logger   *slog.Logger
+		interval time.Duration
+
+		mu       sync.Mutex
+		checks   map[string]*logState
+		statuses map[Probe]Status
+	}
+
+	// logState is the logging state of a single check.
+	logState struct {
+		status Status
+		// failing is set when the last result is a failure, including the one below the failure threshold
+		failing bool
+		// logged is the time of the last failure log record
+		logged time.Time
+		// suppressed is the number of the failures not logged since the last record
+		suppressed int
+	}
+)
+
+// newLogObserver creates the observer logging with the logger
+func newLogObserver(logger *slog.Logger) *logObserver {
+	return &logObserver{
+		logger:   logger,
+		interval: logInterval,
+		checks:   make(map[string]*logState),
+		statuses: make(map[Probe]Status),
+	}
+}
+
+// ObserveCheck implements Observer.
+func (o *logObserver) ObserveCheck(ctx context.Context, name string, r Result) {
+	if r.Status == StatusSkipped || r.Status == StatusDisabled {
+		// skipped checks did not run, the failure of the dependency is logged on its own
+		return
+	}
+
+	now := time.Now()
+
+	o.mu.Lock()
+	state, ok := o.checks[name]
+	if !ok {
+		state = &logState{status: StatusOK}
+		o.checks[name] = state
+	}
+
+	prev, prevFailing := state.status, state.failing
+	// the failure below the failure threshold is reported with StatusOK, but it is a failure still
+	failing := r.Error != ""
+	state.status, state.failing = r.Status, failing
+
+	changed := prev != r.Status
+	suppressed := state.suppressed
+
+	var log bool
+	switch {
+	case !failing:
+		log = changed || prevFailing
+		state.suppressed = 0
+	case changed || now.Sub(state.logged) >= o.interval:
+		log = true
+		state.logged = now
+		state.suppressed = 0
+	default:
+		state.suppressed++
+	}
+	o.mu.Unlock()
+
+	if !log {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("check", name),
+		slog.String("status", string(r.Status)),
+		slog.String("previous_status", string(prev)),
+		slog.Duration("duration", r.Duration),
+		slog.Bool("skip_on_err", r.SkipOnErr),
+	}
+
+	if !failing {
+		o.logger.LogAttrs(ctx, slog.LevelInfo, "health check recovered", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.String("error", r.Error))
+	if r.Attempts > 1 {
+		attrs = append(attrs, slog.Int("attempts", r.Attempts))
+	}
+	if r.Stack != "" {
+		attrs = append(attrs, slog.String("stack", r.Stack), slog.Int("panics", r.Panics))
+	}
+	if r.Stuck {
+		attrs = append(attrs, slog.Duration("stuck_for", r.StuckFor))
+	}
+	if suppressed > 0 && !changed {
+		attrs = append(attrs, slog.Int("suppressed", suppressed))
+	}
+
+	level := slog.LevelError
+	if r.SkipOnErr || r.Status == StatusPartiallyAvailable || r.Status == StatusOK {
+		level = slog.LevelWarn
+	}
+
+	msg := "health check failed"
+	switch {
+	case r.Stuck:
+		msg = "health check is stuck"
+	case r.Status == StatusTimeout:
+		msg = "health check timed out"
+	case r.Stack != "":
+		msg = "health check panicked"
+	}
+
+	o.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// ObserveUnregister implements UnregisterObserver.
+func (o *logObserver) ObserveUnregister(_ context.Context, name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.checks, name)
+}
+
+// ObserveStatus implements Observer.
+func (o *logObserver) ObserveStatus(ctx context.Context, probe Probe, s Status) {
+	o.mu.Lock()
+	prev, ok := o.statuses[probe]
+	if !ok {
+		prev = StatusOK
+	}
+	o.statuses[probe] = s
+	o.mu.Unlock()
+
+	if prev == s {
+		return
+	}
+
+	level := slog.LevelWarn
+	if s == StatusOK {
+		level = slog.LevelInfo
+	}
+
+	o.logger.LogAttrs(ctx, level, "health status changed",
+		slog.String("probe", string(probe)),
+		slog.String("status", string(s)),
+		slog.String("previous_status", string(prev)),
+	)
+}
@@ -0,0 +1,174 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordHandler collects the log records
+type recordHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordHandler) WithGroup(string) slog.Handler { return h }
+
+// messages returns the messages of the collected records
+func (h *recordHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	messages := make([]string, 0, len(h.records))
+	for _, r := range h.records {
+		messages = append(messages, r.Message)
+	}
+
+	return messages
+}
+
+// attrs returns the attributes of the n-th collected record
+func (h *recordHandler) attrs(n int) map[string]any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	attrs := make(map[string]any)
+	h.records[n].Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+
+	return attrs
+}
+
+func TestHealth_Measure_Logger(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+
+	handler := &recordHandler{}
+	h, err := New(
+		WithChecks(Config{
+			Name: "postgres",
+			Check: func(context.Context) error {
+				if failing.Load() {
+					return errors.New(checkErr)
+				}
+				return nil
+			},
+		}, Config{
+			Name:      "redis",
+			SkipOnErr: true,
+			Timeout:   10 * time.Millisecond,
+			Check: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}),
+		WithLogger(slog.New(handler)),
+	)
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	// persistent failures are rate limited, so the second measurement is not logged
+	messages := handler.messages()
+	require.Len(t, messages, 3)
+	assert.ElementsMatch(t, []string{"health check failed", "health check timed out"}, messages[:2])
+	assert.Equal(t, "health status changed", messages[2])
+
+	for i, msg := range messages[:2] {
+		attrs := handler.attrs(i)
+		switch msg {
+		case "health check failed":
+			assert.Equal(t, "postgres", attrs["check"])
+			assert.Equal(t, checkErr, attrs["error"])
+			assert.Equal(t, false, attrs["skip_on_err"])
+			assert.Equal(t, string(StatusUnavailable), attrs["status"])
+			assert.Contains(t, attrs, "duration")
+		case "health check timed out":
+			assert.Equal(t, "redis", attrs["check"])
+			assert.Equal(t, true, attrs["skip_on_err"])
+		}
+	}
+
+	failing.Store(false)
+	h.Measure(context.Background())
+
+	messages = handler.messages()
+	require.Len(t, messages, 5)
+	assert.Equal(t, []string{"health check recovered", "health status changed"}, messages[3:])
+	assert.Equal(t, "postgres", handler.attrs(3)["check"])
+	assert.Equal(t, string(StatusPartiallyAvailable), handler.attrs(4)["status"])
+	assert.Equal(t, string(StatusUnavailable), handler.attrs(4)["previous_status"])
+}
+
+func TestLogObserver_RateLimit(t *testing.T) {
+	handler := &recordHandler{}
+	o := newLogObserver(slog.New(handler))
+	o.interval = 50 * time.Millisecond
+
+	failure := Result{Status: StatusUnavailable, Error: checkErr}
+	for range 3 {
+		o.ObserveCheck(context.Background(), "postgres", failure)
+	}
+	assert.Len(t, handler.messages(), 1)
+
+	time.Sleep(o.interval)
+	o.ObserveCheck(context.Background(), "postgres", failure)
+	require.Len(t, handler.messages(), 2)
+	assert.Equal(t, int64(2), handler.attrs(1)["suppressed"])
+
+	// status transitions are logged regardless of the rate limit
+	o.ObserveCheck(context.Background(), "postgres", Result{Status: StatusTimeout, Error: string(StatusTimeout)})
+	o.ObserveCheck(context.Background(), "postgres", Result{Status: StatusOK})
+	o.ObserveCheck(context.Background(), "postgres", failure)
+	assert.Equal(t, []string{
+		"health check failed",
+		"health check failed",
+		"health check timed out",
+		"health check recovered",
+		"health check failed",
+	}, handler.messages())
+}
+
+func TestLogObserver_BelowFailureThreshold(t *testing.T) {
+	handler := &recordHandler{}
+	o := newLogObserver(slog.New(handler))
+
+	// the failures below the failure threshold are reported with StatusOK
+	tolerated := Result{Status: StatusOK, Error: checkErr, ConsecutiveFailures: 1}
+	o.ObserveCheck(context.Background(), "postgres", tolerated)
+	require.Equal(t, []string{"health check failed"}, handler.messages())
+	assert.Equal(t, checkErr, handler.attrs(0)["error"])
+	assert.Equal(t, slog.LevelWarn, handler.records[0].Level)
+
+	o.ObserveCheck(context.Background(), "postgres", Result{Status: StatusUnavailable, Error: checkErr})
+	o.ObserveCheck(context.Background(), "postgres", Result{Status: StatusOK})
+	o.ObserveCheck(context.Background(), "postgres", Result{Status: StatusOK})
+	assert.Equal(t, []string{
+		"health check failed",
+		"health check failed",
+		"health check recovered",
+	}, handler.messages())
+	assert.Equal(t, slog.LevelError, handler.records[1].Level)
+}
@@ -0,0 +1,194 @@
+package health
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// plan is the snapshot of the checks to be evaluated by a single Measure call.
+	plan struct {
+		probe   Probe
+		started bool
+		// run holds the checks to execute, or to read cached results for when the checks run in the background
+		run []Config
+		// states holds the states of the checks to execute at the time the plan was made
+		states map[string]*checkState
+		// passed holds the startup checks that have already passed
+		passed   []Config
+		disabled []Config
+	}
+
+	// Selector restricts the checks evaluated by MeasureSelected. Empty selector matches all the checks.
+	Selector struct {
+		// Probe restricts the checks to the ones that belong to the probe kind.
+		Probe Probe
+		// Names restricts the checks to the ones with the given names.
+		Names []string
+		// Exclude excludes the checks with the given names.
+		Exclude []string
+		// Tags restricts the checks to the ones having at least one of the given tags.
+		Tags []string
+	}
+
+	// flight is a check evaluation shared by the concurrent Measure calls of the same checks.
+	flight struct {
+		done    chan struct{}
+		results []checkResult
+	}
+)
+
+// newPlan takes the snapshot of the checks that match the selector, must be called with h.mu held
+func (h *Health) newPlan(sel Selector) (plan, error) {
+	if err := h.validateSelector(sel); err != nil {
+		return plan{}, err
+	}
+
+	p := plan{
+		probe:   sel.Probe,
+		started: h.started,
+		states:  make(map[string]*checkState),
+	}
+
+	for _, c := range h.checks {
+		state := h.states[c.Name]
+		switch {
+		case !sel.matches(c):
+		case state.disabled:
+			p.disabled = append(p.disabled, c)
+		case state.startupPassed && (sel.Probe == ProbeStartup || c.startupOnly()):
+			// startup checks that have already passed are not executed again, the ones that belong
+			// to the other probe kinds too keep running for them
+			p.passed = append(p.passed, c)
+		default:
+			p.run = append(p.run, c)
+			p.states[c.Name] = state
+		}
+	}
+
+	return p, nil
+}
+
+// validateSelector checks that the selector refers to the registered checks only, must be called with h.mu held
+func (h *Health) validateSelector(sel Selector) error {
+	var unknown []string
+	for _, name := range slices.Concat(sel.Names, sel.Exclude) {
+		if _, ok := h.checks[name]; !ok && !slices.Contains(unknown, name) {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	return &UnknownCheckError{
+		Unknown:    unknown,
+		Registered: slices.Sorted(maps.Keys(h.checks)),
+	}
+}
+
+// matches reports whether the check matches the selector
+func (sel Selector) matches(c Config) bool {
+	switch {
+	case sel.Probe != "" && !c.hasProbe(sel.Probe):
+		return false
+	case len(sel.Names) > 0 && !slices.Contains(sel.Names, c.Name):
+		return false
+	case slices.Contains(sel.Exclude, c.Name):
+		return false
+	case len(sel.Tags) > 0 && !slices.ContainsFunc(sel.Tags, func(tag string) bool {
+		return slices.Contains(c.Tags, tag)
+	}):
+		return false
+	}
+
+	return true
+}
+
+// filtered reports whether the selector restricts the checks further than by the probe kind
+func (sel Selector) filtered() bool {
+	return len(sel.Names) > 0 || len(sel.Exclude) > 0 || len(sel.Tags) > 0
+}
+
+// key identifies the set of checks to execute, so that the same evaluations can be coalesced
+func (p plan) key() string {
+	names := make([]string, 0, len(p.run))
+	for _, c := range p.run {
+		names = append(names, c.Name)
+	}
+	slices.Sort(names)
+
+	return string(p.probe) + ":" + strings.Join(names, ",")
+}
+
+// runCoalesced executes the planned checks, or waits for the results of the same evaluation already
+// in flight until the context is done. Reports whether the results were shared with another caller.
+func (h *Health) runCoalesced(ctx context.Context, tracer trace.Tracer, p plan) ([]checkResult, bool) {
+	key := p.key()
+
+	h.mu.Lock()
+	if f, ok := h.flights[key]; ok {
+		h.mu.Unlock()
+
+		select {
+		case <-f.done:
+			return f.results, true
+		case <-ctx.Done():
+			// the evaluation keeps running for the other callers, while this one gives up on its own context
+			return abortedResults(p.run, ctx.Err()), true
+		}
+	}
+
+	f := &flight{done: make(chan struct{})}
+	h.flights[key] = f
+	h.mu.Unlock()
+
+	// callers sharing the evaluation must not be affected by the cancellation of the first one
+	ctx = context.WithoutCancel(ctx)
+	f.results = h.runChecks(ctx, tracer, p.run, func(res checkResult) checkResult {
+		h.mu.Lock()
+		res = h.storeResult(p.states[res.name], res)
+		r := h.newResult(res)
+		// the result of the check unregistered or replaced meanwhile is not observed, so that
+		// the observers do not keep the check they have already dropped
+		current := h.states[res.name] == p.states[res.name]
+		h.mu.Unlock()
+
+		if current {
+			h.observeCheck(ctx, res.name, r)
+		}
+
+		return res
+	})
+
+	h.mu.Lock()
+	delete(h.flights, key)
+	h.mu.Unlock()
+
+	close(f.done)
+
+	return f.results, false
+}
+
+// abortedResults returns the results of the checks the caller stopped waiting for
+func abortedResults(checks []Config, err error) []checkResult {
+	now := time.Now()
+
+	results := make([]checkResult, 0, len(checks))
+	for _, c := range checks {
+		results = append(results, checkResult{
+			name:      c.Name,
+			skipOnErr: c.SkipOnErr,
+			err:       err,
+			startedAt: now,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,217 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_MeasureCoalesced(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			calls.Add(1)
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	results := make([]Check, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = h.Measure(context.Background())
+		}(i)
+	}
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// registration must not wait for the checks in flight
+	err = h.Register(Config{
+		Name:  "redis",
+		Check: func(context.Context) error { return nil },
+	})
+	require.NoError(t, err)
+
+	// let all the callers join the evaluation in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "concurrent measures should share the same evaluation")
+	for _, result := range results {
+		assert.Equal(t, StatusOK, result.Status)
+		assert.Equal(t, results[0].Results["postgres"], result.Results["postgres"])
+	}
+
+	h.Measure(context.Background())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestHealth_MeasureCoalescedContext(t *testing.T) {
+	var changes atomic.Int32
+	release := make(chan struct{})
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			<-release
+			return nil
+		},
+	}), WithStatusListener(func(Probe, Status, Status) {
+		changes.Add(1)
+	}))
+	require.NoError(t, err)
+
+	leader := make(chan Check)
+	go func() { leader <- h.Measure(context.Background()) }()
+
+	require.Eventually(t, func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return len(h.flights) == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := h.Measure(ctx)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "caller joining the evaluation should not outlive its context")
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), result.Failures["postgres"])
+
+	close(release)
+	assert.Equal(t, StatusOK, (<-leader).Status, "evaluation should keep running for the other callers")
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, changes.Load(), "caller that gave up should not report the status")
+}
+
+func TestHealth_MeasureNotCoalescedForDifferentProbes(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h, err := New(WithChecks(Config{
+		Name:   "postgres",
+		Probes: []Probe{ProbeLiveness, ProbeReadiness},
+		Check: func(context.Context) error {
+			calls.Add(1)
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, measure := range []func(context.Context) Check{h.MeasureLiveness, h.MeasureReadiness} {
+		wg.Add(1)
+		go func(measure func(context.Context) Check) {
+			defer wg.Done()
+			measure(context.Background())
+		}(measure)
+	}
+
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestHealth_MeasureSelected(t *testing.T) {
+	var calls []string
+	check := func(name string) CheckFunc {
+		return func(context.Context) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Tags:  []string{"critical", "db"},
+		Check: check("postgres"),
+	}, Config{
+		Name:  "redis",
+		Tags:  []string{"db"},
+		Check: check("redis"),
+	}, Config{
+		Name:   "rabbitmq",
+		Probes: []Probe{ProbeLiveness},
+		Check:  check("rabbitmq"),
+	}), WithMaxConcurrent(1))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		sel      Selector
+		expected []string
+	}{
+		{sel: Selector{}, expected: []string{"postgres", "rabbitmq", "redis"}},
+		{sel: Selector{Names: []string{"postgres", "redis"}}, expected: []string{"postgres", "redis"}},
+		{sel: Selector{Exclude: []string{"rabbitmq"}}, expected: []string{"postgres", "redis"}},
+		{sel: Selector{Tags: []string{"critical"}}, expected: []string{"postgres"}},
+		{sel: Selector{Tags: []string{"db"}, Exclude: []string{"postgres"}}, expected: []string{"redis"}},
+		{sel: Selector{Probe: ProbeReadiness, Names: []string{"rabbitmq"}}, expected: nil},
+	} {
+		calls = nil
+
+		result, err := h.MeasureSelected(context.Background(), tc.sel)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, tc.expected, calls, "%+v", tc.sel)
+		assert.Len(t, result.Results, len(tc.expected))
+	}
+
+	_, err = h.MeasureSelected(context.Background(), Selector{
+		Names:   []string{"postgres", "mysql"},
+		Exclude: []string{"mongodb", "mysql"},
+	})
+	var unknownErr *UnknownCheckError
+	require.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, []string{"mysql", "mongodb"}, unknownErr.Unknown)
+	assert.Equal(t, []string{"postgres", "rabbitmq", "redis"}, unknownErr.Registered)
+	assert.EqualError(t, err, "unknown health checks: mysql, mongodb; registered health checks: postgres, rabbitmq, redis")
+}
+
+func TestHealthHandlerSelected(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Tags:  []string{"critical"},
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:  "rabbitmq",
+		Check: func(context.Context) error { return errors.New("rabbitmq") },
+	}))
+	require.NoError(t, err)
+
+	for target, code := range map[string]int{
+		"/status":                               http.StatusServiceUnavailable,
+		"/status?check=postgres":                http.StatusOK,
+		"/status?exclude=rabbitmq":              http.StatusOK,
+		"/status?tag=critical":                  http.StatusOK,
+		"/status?check=postgres&check=rabbitmq": http.StatusServiceUnavailable,
+		"/status?check=mysql":                   http.StatusBadRequest,
+	} {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		h.HandlerFunc(res, req)
+
+		assert.Equal(t, code, res.Code, target)
+	}
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/status?exclude=mysql", nil)
+	h.ReadinessHandler().ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), "registered health checks: postgres, rabbitmq")
+}
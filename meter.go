@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterObserver records the check results with OpenTelemetry metric instruments.
+type meterObserver struct {
+	duration   metric.Float64Histogram
+	executions metric.Int64Counter
+	timeouts   metric.Int64Counter
+	panics     metric.Int64Counter
+	status     metric.Int64Gauge
+}
+
+// newMeterObserver creates the instruments with the meter
+func newMeterObserver(meter metric.Meter) (*meterObserver, error) {
+	duration, err := meter.Float64Histogram(
+		"health.check.duration",
+		metric.WithDescription("Duration of the health check executions."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create check duration instrument: %w", err)
+	}
+
+	executions, err := meter.Int64Counter(
+		"health.check.executions",
+		metric.WithDescription("Number of the health check executions by their outcome."),
+		metric.WithUnit("{execution}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create check executions instrument: %w", err)
+	}
+
+	timeouts, err := meter.Int64Counter(
+		"health.check.timeouts",
+		metric.WithDescription("Number of the health check executions that timed out."),
+		metric.WithUnit("{timeout}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create check timeouts instrument: %w", err)
+	}
+
+	panics, err := meter.Int64Counter(
+		"health.check.panics",
+		metric.WithDescription("Number of the health check executions that panicked."),
+		metric.WithUnit("{panic}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create check panics instrument: %w", err)
+	}
+
+	status, err := meter.Int64Gauge(
+		"health.status",
+		metric.WithDescription("Summary health status, 1 for the current status and 0 for the others."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create status instrument: %w", err)
+	}
+
+	return &meterObserver{
+		duration:   duration,
+		executions: executions,
+		timeouts:   timeouts,
+		panics:     panics,
+		status:     status,
+	}, nil
+}
+
+// ObserveCheck implements Observer.
+func (o *meterObserver) ObserveCheck(ctx context.Context, name string, r Result) {
+	checkAttr := attribute.String("health.check.name", name)
+
+	if r.Executed() {
+		o.duration.Record(ctx, r.Duration.Seconds(), metric.WithAttributes(checkAttr))
+	}
+	o.executions.Add(ctx, 1, metric.WithAttributes(checkAttr, attribute.String("health.check.status", string(r.Status))))
+	if r.TimedOut() {
+		o.timeouts.Add(ctx, 1, metric.WithAttributes(checkAttr))
+	}
+	if r.Stack != "" {
+		o.panics.Add(ctx, 1, metric.WithAttributes(checkAttr))
+	}
+}
+
+// ObserveStatus implements Observer.
+func (o *meterObserver) ObserveStatus(ctx context.Context, probe Probe, s Status) {
+	for _, status := range []Status{StatusOK, StatusPartiallyAvailable, StatusUnavailable} {
+		var value int64
+		if status == s {
+			value = 1
+		}
+
+		o.status.Record(ctx, value, metric.WithAttributes(
+			attribute.String("health.probe", string(probe)),
+			attribute.String("health.status", string(status)),
+		))
+	}
+}
@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestWithMeterProvider(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	h, err := New(WithMeterProvider(mp, "test.test"), WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:      "redis",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New("redis") },
+	}, Config{
+		Name:      "broken",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { panic("nil map") },
+	}, Config{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Check: func(context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	h.MeasureLiveness(context.Background())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, "test.test", rm.ScopeMetrics[0].Scope.Name)
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	duration, ok := metrics["health.check.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Len(t, duration.DataPoints, 4)
+
+	executions, ok := metrics["health.check.executions"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.ElementsMatch(t, []string{
+		"postgres/OK",
+		"redis/Partially Available",
+		"broken/Partially Available",
+		"slow/Timeout during health check",
+	}, dataPointKeys(executions.DataPoints, "health.check.name", "health.check.status"))
+
+	timeouts, ok := metrics["health.check.timeouts"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, timeouts.DataPoints, 1)
+	assert.Equal(t, int64(1), timeouts.DataPoints[0].Value)
+
+	panics, ok := metrics["health.check.panics"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.Equal(t, []string{"broken"}, dataPointKeys(panics.DataPoints, "health.check.name"))
+
+	status, ok := metrics["health.status"].(metricdata.Gauge[int64])
+	require.True(t, ok)
+	current := make(map[string]int64)
+	for _, dp := range status.DataPoints {
+		current[dataPointKeys([]metricdata.DataPoint[int64]{dp}, "health.probe", "health.status")[0]] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{
+		"/OK":                          0,
+		"/Partially Available":         0,
+		"/Unavailable":                 1,
+		"liveness/OK":                  1,
+		"liveness/Partially Available": 0,
+		"liveness/Unavailable":         0,
+	}, current)
+}
+
+func dataPointKeys(dps []metricdata.DataPoint[int64], keys ...attribute.Key) []string {
+	result := make([]string, 0, len(dps))
+	for _, dp := range dps {
+		var key string
+		for i, k := range keys {
+			v, _ := dp.Attributes.Value(k)
+			if i > 0 {
+				key += "/"
+			}
+			key += v.AsString()
+		}
+		result = append(result, key)
+	}
+
+	return result
+}
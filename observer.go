@@ -0,0 +1,43 @@
+package health
+
+import "context"
+
+// Observer receives the check results as they are produced, e.g. to export them as metrics.
+// Observer methods are called synchronously, so they must not block.
+type Observer interface {
+	// ObserveCheck is called with the result of every check execution.
+	ObserveCheck(ctx context.Context, name string, r Result)
+	// ObserveStatus is called with the summary status of the probe kind after every measurement.
+	// Probe is empty for the summary status of all the checks.
+	ObserveStatus(ctx context.Context, probe Probe, s Status)
+}
+
+// UnregisterObserver is implemented by the observers that keep the state of every check, e.g. the metric series,
+// to drop it once the check is unregistered.
+type UnregisterObserver interface {
+	// ObserveUnregister is called after the check is unregistered, its results in flight are not observed anymore.
+	ObserveUnregister(ctx context.Context, name string)
+}
+
+// observeCheck passes the check result to the observers
+func (h *Health) observeCheck(ctx context.Context, name string, r Result) {
+	for _, o := range h.observers {
+		o.ObserveCheck(ctx, name, r)
+	}
+}
+
+// observeStatus passes the summary status to the observers
+func (h *Health) observeStatus(ctx context.Context, probe Probe, s Status) {
+	for _, o := range h.observers {
+		o.ObserveStatus(ctx, probe, s)
+	}
+}
+
+// observeUnregister notifies the observers implementing UnregisterObserver that the check is unregistered
+func (h *Health) observeUnregister(ctx context.Context, name string) {
+	for _, o := range h.observers {
+		if uo, ok := o.(UnregisterObserver); ok {
+			uo.ObserveUnregister(ctx, name)
+		}
+	}
+}
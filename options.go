@@ -2,7 +2,9 @@ package health
 
 import (
 	"fmt"
+	"log/slog"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -12,7 +14,8 @@ type Option func(*Health) error
 // WithChecks adds checks to newly instantiated health-container
 func WithChecks(checks ...Config) Option {
 	return func(h *Health) error {
-		for _, c := range checks {
+		// the checks may depend on the ones given after them, so they are registered dependencies first
+		for _, c := range sortByDependencies(checks) {
 			if err := h.Register(c); err != nil {
 				return fmt.Errorf("could not register check %q: %w", c.Name, err)
 			}
@@ -33,6 +36,26 @@ func WithTracerProvider(tp trace.TracerProvider, instrumentationName string) Opt
 	}
 }
 
+// WithMeterProvider sets meter provider for the checks metrics and instrumentation name that will be used
+// for meter from meter provider. The following instruments are recorded:
+// - health.check.duration - histogram of the check durations, by check name
+// - health.check.executions - number of the check executions, by check name and status
+// - health.check.timeouts - number of the check timeouts, by check name
+// - health.check.panics - number of the check panics, by check name
+// - health.status - summary status, set to 1 for the current status, by probe kind and status
+func WithMeterProvider(mp metric.MeterProvider, instrumentationName string) Option {
+	return func(h *Health) error {
+		o, err := newMeterObserver(mp.Meter(instrumentationName))
+		if err != nil {
+			return err
+		}
+
+		h.observers = append(h.observers, o)
+
+		return nil
+	}
+}
+
 // WithComponent sets the component description of the component to which this check refer
 func WithComponent(component Component) Option {
 	return func(h *Health) error {
@@ -51,6 +74,19 @@ func WithMaxConcurrent(n int) Option {
 	}
 }
 
+// WithHistorySize sets the number of the last results kept in the history of every check.
+// Set to 0 to disable the history. If not set - 100
+func WithHistorySize(n int) Option {
+	return func(h *Health) error {
+		if n < 0 {
+			return fmt.Errorf("invalid history size %d", n)
+		}
+
+		h.historySize = n
+		return nil
+	}
+}
+
 // WithSystemInfo enables the option to return system information about the go process.
 func WithSystemInfo() Option {
 	return func(h *Health) error {
@@ -58,3 +94,86 @@ func WithSystemInfo() Option {
 		return nil
 	}
 }
+
+// WithStatusListener adds the listener notified when the summary status changes.
+// Listeners are called asynchronously, one at a time, in the order of the changes.
+func WithStatusListener(l StatusListener) Option {
+	return func(h *Health) error {
+		h.statusListeners = append(h.statusListeners, l)
+		return nil
+	}
+}
+
+// WithCheckStatusListener adds the listener notified when a check starts or stops failing.
+// Listeners are called asynchronously, one at a time, in the order of the changes.
+func WithCheckStatusListener(l CheckStatusListener) Option {
+	return func(h *Health) error {
+		h.checkStatusListeners = append(h.checkStatusListeners, l)
+		return nil
+	}
+}
+
+// WithObserver adds the observer that receives every check result and summary status.
+func WithObserver(o Observer) Option {
+	return func(h *Health) error {
+		h.observers = append(h.observers, o)
+		return nil
+	}
+}
+
+// WithFormat sets the default format of the HTTP handlers response, used when the request
+// Accept header does not ask for any of the supported formats.
+func WithFormat(f Format) Option {
+	return func(h *Health) error {
+		if _, ok := renderers[f]; !ok {
+			return fmt.Errorf("unsupported response format %q", f)
+		}
+
+		h.format = f
+		return nil
+	}
+}
+
+// WithStatusCodes sets the HTTP status codes of the handlers responses by the summary status,
+// overriding the default 200 for StatusOK and StatusPartiallyAvailable and 503 for StatusUnavailable.
+// StatusTimeout code, if set, is used when the service is unavailable because of a check timeout.
+func WithStatusCodes(codes map[Status]int) Option {
+	return func(h *Health) error {
+		for s, code := range codes {
+			if code < 100 || code > 599 {
+				return fmt.Errorf("invalid HTTP status code %d for status %q", code, s)
+			}
+
+			h.statusCodes[s] = code
+		}
+
+		return nil
+	}
+}
+
+// WithFailureDetails sets whether the handlers responses include the failure messages of the checks.
+// Failure messages are included by default.
+func WithFailureDetails(enabled bool) Option {
+	return func(h *Health) error {
+		h.hideFailureDetails = !enabled
+		return nil
+	}
+}
+
+// WithAuthorizer makes the handlers respond with the summary status only, unless the request is approved
+// by the authorizer, e.g. BearerTokenAuthorizer or BasicAuthAuthorizer, to see the detailed response.
+func WithAuthorizer(a Authorizer) Option {
+	return func(h *Health) error {
+		h.authorizer = a
+		return nil
+	}
+}
+
+// WithLogger sets the logger for the check failures, timeouts and status transitions.
+// Failures of the persistently failing check are logged at most once a minute.
+func WithLogger(logger *slog.Logger) Option {
+	return func(h *Health) error {
+		h.observers = append(h.observers, newLogObserver(logger))
+		return nil
+	}
+}
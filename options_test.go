@@ -1,7 +1,11 @@
 package health
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"runtime"
 	"testing"
 
@@ -97,3 +101,91 @@ func TestWithSystemInfo(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, h2.systemInfoEnabled)
 }
+
+func TestWithStatusListener(t *testing.T) {
+	h1, err := New()
+	require.NoError(t, err)
+	assert.Empty(t, h1.statusListeners)
+	assert.Empty(t, h1.checkStatusListeners)
+
+	h2, err := New(
+		WithStatusListener(func(Probe, Status, Status) {}),
+		WithStatusListener(func(Probe, Status, Status) {}),
+		WithCheckStatusListener(func(string, Status, Status) {}),
+	)
+	require.NoError(t, err)
+	assert.Len(t, h2.statusListeners, 2)
+	assert.Len(t, h2.checkStatusListeners, 1)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) ObserveCheck(context.Context, string, Result) {}
+
+func (nopObserver) ObserveStatus(context.Context, Probe, Status) {}
+
+func TestWithObserver(t *testing.T) {
+	h1, err := New()
+	require.NoError(t, err)
+	assert.Empty(t, h1.observers)
+
+	h2, err := New(WithObserver(nopObserver{}))
+	require.NoError(t, err)
+	assert.Equal(t, []Observer{nopObserver{}}, h2.observers)
+}
+
+func TestWithStatusCodes(t *testing.T) {
+	h1, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, map[Status]int{
+		StatusOK:                 http.StatusOK,
+		StatusPartiallyAvailable: http.StatusOK,
+		StatusUnavailable:        http.StatusServiceUnavailable,
+	}, h1.statusCodes)
+
+	h2, err := New(WithStatusCodes(map[Status]int{
+		StatusPartiallyAvailable: http.StatusTooManyRequests,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, h2.statusCodes[StatusPartiallyAvailable])
+	assert.Equal(t, http.StatusServiceUnavailable, h2.statusCodes[StatusUnavailable])
+
+	_, err = New(WithStatusCodes(map[Status]int{StatusOK: 42}))
+	require.Error(t, err)
+}
+
+func TestWithFailureDetails(t *testing.T) {
+	h1, err := New()
+	require.NoError(t, err)
+	assert.False(t, h1.hideFailureDetails)
+
+	h2, err := New(WithFailureDetails(false))
+	require.NoError(t, err)
+	assert.True(t, h2.hideFailureDetails)
+}
+
+func TestWithLogger(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	h, err := New(WithLogger(logger))
+	require.NoError(t, err)
+	require.Len(t, h.observers, 1)
+
+	o, ok := h.observers[0].(*logObserver)
+	require.True(t, ok)
+	assert.Same(t, logger, o.logger)
+	assert.Equal(t, logInterval, o.interval)
+}
+
+func TestWithHistorySize(t *testing.T) {
+	h1, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, defaultHistorySize, h1.historySize)
+
+	h2, err := New(WithHistorySize(10))
+	require.NoError(t, err)
+	assert.Equal(t, 10, h2.historySize)
+
+	_, err = New(WithHistorySize(-1))
+	require.Error(t, err)
+}
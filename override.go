@@ -0,0 +1,335 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"time"
+)
+
+// defaultOverrideReason is the failure message of the overridden check when the override has no reason
+const defaultOverrideReason = "status overridden"
+
+var errNotRegistered = errors.New("not registered")
+
+type (
+	// Override forces the status reported for the service or for a single check, e.g. to take the service
+	// out of rotation for maintenance, or to ignore a known broken dependency.
+	Override struct {
+		// Status is the forced status, one of StatusOK, StatusPartiallyAvailable and StatusUnavailable.
+		Status Status `json:"status"`
+		// Reason explains why the status is forced.
+		Reason string `json:"reason,omitempty"`
+		// Author is the one who forced the status.
+		Author string `json:"author,omitempty"`
+		// CreatedAt is the time in which the override was set.
+		CreatedAt time.Time `json:"created_at"`
+		// Expires is the time in which the override is cleared automatically, nil if it is cleared manually only.
+		Expires *time.Time `json:"expires,omitempty"`
+	}
+
+	// Overrides holds the active overrides.
+	Overrides struct {
+		// Status is the override of the service status, nil if there is none.
+		Status *Override `json:"status,omitempty"`
+		// Checks holds the overrides of the check results by check name.
+		Checks map[string]Override `json:"checks,omitempty"`
+	}
+
+	// overrideRequest is the body of the override handler request.
+	overrideRequest struct {
+		Override
+		// TTL is the time the override is active for, as an alternative to Expires, e.g. "30m".
+		TTL string `json:"ttl,omitempty"`
+	}
+)
+
+// OverrideStatus forces the summary status of readiness and of all the checks, e.g. StatusUnavailable
+// for the maintenance mode. The checks keep running, their results are reported as is.
+func (h *Health) OverrideStatus(o Override) error {
+	o, err := newOverride(o)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.statusOverride = &o
+
+	return nil
+}
+
+// OverrideCheck forces the result of the check. The check keeps running, so that the observers and
+// the listeners still get its actual results, but its reported status is the forced one.
+func (h *Health) OverrideCheck(name string, o Override) error {
+	o, err := newOverride(o)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[name]; !ok {
+		return fmt.Errorf("health check %q is %w", name, errNotRegistered)
+	}
+
+	h.checkOverrides[name] = o
+
+	return nil
+}
+
+// ClearStatusOverride clears the override of the summary status.
+func (h *Health) ClearStatusOverride() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.statusOverride = nil
+}
+
+// ClearCheckOverride clears the override of the check result.
+func (h *Health) ClearCheckOverride(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[name]; !ok {
+		return fmt.Errorf("health check %q is %w", name, errNotRegistered)
+	}
+
+	delete(h.checkOverrides, name)
+
+	return nil
+}
+
+// Overrides returns the active overrides.
+func (h *Health) Overrides() Overrides {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.expireOverrides()
+
+	var res Overrides
+	if h.statusOverride != nil {
+		o := *h.statusOverride
+		res.Status = &o
+	}
+	if len(h.checkOverrides) > 0 {
+		res.Checks = maps.Clone(h.checkOverrides)
+	}
+
+	return res
+}
+
+// OverrideHandler returns an HTTP handler to manage the overrides:
+// - GET responds with the active overrides
+// - PUT sets the override from the JSON request body, of the check given with check query parameter,
+// or of the summary status otherwise
+// - DELETE clears the override of the check given with check query parameter, or of the summary status otherwise
+//
+// Requests are rejected unless approved by the authorizer, that is separate from the one set with WithAuthorizer,
+// as the overrides change the reported health rather than only read it. All requests are rejected if it is nil.
+func (h *Health) OverrideHandler(a Authorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a == nil || !a(r) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		name := r.URL.Query().Get("check")
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req overrideRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("invalid override: %s", err), http.StatusBadRequest)
+				return
+			}
+
+			o, err := req.override()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if name == "" {
+				err = h.OverrideStatus(o)
+			} else {
+				err = h.OverrideCheck(name, o)
+			}
+			if err != nil {
+				writeOverrideError(w, err)
+				return
+			}
+		case http.MethodDelete:
+			if name == "" {
+				h.ClearStatusOverride()
+			} else if err := h.ClearCheckOverride(name); err != nil {
+				writeOverrideError(w, err)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		data, err := json.Marshal(h.Overrides())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// writeOverrideError writes the error of the override request
+func writeOverrideError(w http.ResponseWriter, err error) {
+	code := http.StatusBadRequest
+	if errors.Is(err, errNotRegistered) {
+		code = http.StatusNotFound
+	}
+
+	http.Error(w, err.Error(), code)
+}
+
+// override returns the override of the request, with the expiry time set from the TTL if any
+func (req overrideRequest) override() (Override, error) {
+	o := req.Override
+	if req.TTL == "" {
+		return o, nil
+	}
+
+	ttl, err := time.ParseDuration(req.TTL)
+	if err != nil {
+		return Override{}, fmt.Errorf("invalid override ttl: %w", err)
+	}
+	if ttl <= 0 {
+		return Override{}, fmt.Errorf("invalid override ttl %s: must be positive", req.TTL)
+	}
+
+	expires := time.Now().Add(ttl)
+	o.Expires = &expires
+
+	return o, nil
+}
+
+// newOverride validates the override, that must not be expired already, and sets its creation time
+func newOverride(o Override) (Override, error) {
+	switch o.Status {
+	case StatusOK, StatusPartiallyAvailable, StatusUnavailable:
+	default:
+		return Override{}, fmt.Errorf("unsupported override status %q", o.Status)
+	}
+
+	now := time.Now()
+	if o.Expires != nil {
+		if o.expired(now) {
+			return Override{}, fmt.Errorf("override expires in the past: %s", o.Expires.Format(time.RFC3339))
+		}
+
+		expires := *o.Expires
+		o.Expires = &expires
+	}
+	o.CreatedAt = now
+
+	return o, nil
+}
+
+// expired reports whether the override is not active anymore
+func (o Override) expired(now time.Time) bool {
+	return o.Expires != nil && !now.Before(*o.Expires)
+}
+
+// err returns the error the overridden check is reported with
+func (o Override) err() error {
+	reason := o.Reason
+	if reason == "" {
+		reason = defaultOverrideReason
+	}
+
+	switch o.Status {
+	case StatusUnavailable:
+		return errors.New(reason)
+	case StatusPartiallyAvailable:
+		return Degraded(errors.New(reason))
+	}
+
+	return nil
+}
+
+// expireOverrides clears the expired overrides, must be called with h.mu held
+func (h *Health) expireOverrides() {
+	now := time.Now()
+
+	if h.statusOverride != nil && h.statusOverride.expired(now) {
+		h.statusOverride = nil
+	}
+	maps.DeleteFunc(h.checkOverrides, func(_ string, o Override) bool {
+		return o.expired(now)
+	})
+}
+
+// activeStatusOverride returns the override of the summary status of the probe kind, must be called with h.mu held
+func (h *Health) activeStatusOverride(probe Probe) (Override, bool) {
+	h.expireOverrides()
+
+	// liveness is never overridden, so that the service is not restarted while it is out of rotation
+	if h.statusOverride == nil || !drains(probe) {
+		return Override{}, false
+	}
+
+	return *h.statusOverride, true
+}
+
+// overridden returns the results with the check overrides applied, must be called with h.mu held
+func (h *Health) overridden(results []checkResult) []checkResult {
+	h.expireOverrides()
+	if len(h.checkOverrides) == 0 {
+		return results
+	}
+
+	// results may be shared with the coalesced measurements, so they are not modified in place
+	res := make([]checkResult, len(results))
+	for i, r := range results {
+		res[i] = h.overriddenResult(r)
+	}
+
+	return res
+}
+
+// overriddenResult returns the result with the check override applied if there is an active one,
+// must be called with h.mu held
+func (h *Health) overriddenResult(r checkResult) checkResult {
+	o, ok := h.checkOverrides[r.name]
+	if !ok || r.disabled || o.expired(time.Now()) {
+		return r
+	}
+
+	return checkResult{
+		name:      r.name,
+		err:       o.err(),
+		startedAt: r.startedAt,
+		duration:  r.duration,
+		attempts:  r.attempts,
+		override:  &o,
+	}
+}
+
+// reportedStatus returns the summary status of the probe kind the way it is reported given the results
+// of its checks, taking into account the drain mode and the overrides. Must be called with h.mu held.
+func (h *Health) reportedStatus(probe Probe, results []checkResult) Status {
+	if h.draining && drains(probe) {
+		return StatusUnavailable
+	}
+	if o, ok := h.activeStatusOverride(probe); ok {
+		return o.Status
+	}
+
+	return summaryStatus(h.overridden(results))
+}
@@ -0,0 +1,262 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_OverrideStatus(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:   "ping",
+		Probes: []Probe{ProbeLiveness},
+		Check:  func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	err = h.OverrideStatus(Override{Status: StatusTimeout})
+	require.Error(t, err)
+
+	past := time.Now().Add(-time.Minute)
+	err = h.OverrideStatus(Override{Status: StatusUnavailable, Expires: &past})
+	require.Error(t, err)
+	err = h.OverrideCheck("postgres", Override{Status: StatusOK, Expires: &past})
+	require.Error(t, err)
+
+	err = h.OverrideStatus(Override{Status: StatusUnavailable, Reason: "maintenance", Author: "ops"})
+	require.NoError(t, err)
+
+	for _, c := range []Check{h.Measure(context.Background()), h.MeasureReadiness(context.Background())} {
+		assert.Equal(t, StatusUnavailable, c.Status)
+		assert.Equal(t, "maintenance", c.Reason)
+		require.NotNil(t, c.Override)
+		assert.Equal(t, "ops", c.Override.Author)
+		assert.False(t, c.Override.CreatedAt.IsZero())
+		assert.Equal(t, StatusOK, c.Results["postgres"].Status, "checks results are reported as is")
+	}
+
+	c := h.MeasureLiveness(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Nil(t, c.Override)
+
+	h.ClearStatusOverride()
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Nil(t, c.Override)
+	assert.Empty(t, c.Reason)
+}
+
+func TestHealth_OverrideCheck(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:  "redis",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	err = h.OverrideCheck("mysql", Override{Status: StatusOK})
+	require.Error(t, err)
+
+	require.NoError(t, h.OverrideCheck("postgres", Override{Status: StatusOK, Reason: "known issue"}))
+	require.NoError(t, h.OverrideCheck("redis", Override{Status: StatusPartiallyAvailable}))
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusPartiallyAvailable, c.Status)
+	assert.Nil(t, c.Override)
+
+	postgres := c.Results["postgres"]
+	assert.Equal(t, StatusOK, postgres.Status)
+	assert.Empty(t, postgres.Error)
+	require.NotNil(t, postgres.Override)
+	assert.Equal(t, "known issue", postgres.Override.Reason)
+	assert.Equal(t, 1, postgres.ConsecutiveFailures, "actual results are still tracked")
+
+	assert.Equal(t, StatusPartiallyAvailable, c.Results["redis"].Status)
+	assert.Equal(t, defaultOverrideReason, c.Failures["redis"])
+
+	require.NoError(t, h.ClearCheckOverride("redis"))
+	require.NoError(t, h.Unregister("postgres"))
+	assert.Equal(t, Overrides{}, h.Overrides())
+	assert.Error(t, h.ClearCheckOverride("postgres"))
+
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Nil(t, c.Results["redis"].Override)
+}
+
+func TestHealth_OverrideExpiry(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	expires := time.Now().Add(50 * time.Millisecond)
+	require.NoError(t, h.OverrideStatus(Override{Status: StatusUnavailable, Expires: &expires}))
+	require.NoError(t, h.OverrideCheck("postgres", Override{Status: StatusUnavailable, Expires: &expires}))
+
+	overrides := h.Overrides()
+	require.NotNil(t, overrides.Status)
+	assert.Len(t, overrides.Checks, 1)
+	assert.Equal(t, StatusUnavailable, h.Measure(context.Background()).Status)
+
+	time.Sleep(60 * time.Millisecond)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Nil(t, c.Override)
+	assert.Nil(t, c.Results["postgres"].Override)
+	assert.Equal(t, Overrides{}, h.Overrides())
+}
+
+func TestHealth_OverrideStatus_Listener(t *testing.T) {
+	events := make(chan Status, 2)
+	h, err := New(WithStatusListener(func(probe Probe, _, next Status) {
+		if probe == "" {
+			events <- next
+		}
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, h.OverrideStatus(Override{Status: StatusUnavailable}))
+	h.Measure(context.Background())
+
+	select {
+	case s := <-events:
+		assert.Equal(t, StatusUnavailable, s)
+	case <-time.After(time.Second):
+		t.Fatal("status listener was not notified")
+	}
+}
+
+func TestHealth_OverrideHandler(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	h.OverrideHandler(nil).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "http://localhost/overrides", nil))
+	assert.Equal(t, http.StatusForbidden, res.Code, "handler must be forbidden without authorizer")
+
+	h, err = New(
+		WithChecks(Config{
+			Name:  "postgres",
+			Check: func(context.Context) error { return nil },
+		}),
+		WithAuthorizer(BearerTokenAuthorizer("r3ad")),
+	)
+	require.NoError(t, err)
+
+	handler := h.OverrideHandler(BearerTokenAuthorizer("s3cr3t"))
+	do := func(method, target, body string, authorized bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "http://localhost/overrides"+target, strings.NewReader(body))
+		if authorized {
+			req.Header.Set("Authorization", "Bearer s3cr3t")
+		}
+
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		return res
+	}
+
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "", "", false).Code)
+
+	// the read access to the verbose responses does not grant the write access to the overrides
+	req := httptest.NewRequest(http.MethodPut, "http://localhost/overrides", strings.NewReader(`{"status":"Unavailable"}`))
+	req.Header.Set("Authorization", "Bearer r3ad")
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+	assert.Nil(t, h.Overrides().Status)
+
+	res = do(http.MethodPut, "", `{"status":"Unavailable","reason":"maintenance","author":"ops","ttl":"1h"}`, true)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+
+	var overrides Overrides
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &overrides))
+	require.NotNil(t, overrides.Status)
+	assert.Equal(t, "maintenance", overrides.Status.Reason)
+	require.NotNil(t, overrides.Status.Expires)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *overrides.Status.Expires, time.Minute)
+
+	res = do(http.MethodPut, "?check=postgres", `{"status":"Partially Available"}`, true)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.NotNil(t, c.Override)
+	assert.NotNil(t, c.Results["postgres"].Override)
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "?check=mysql", `{"status":"OK"}`, true).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "?check=mysql", "", true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "", `{"status":"Unknown"}`, true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "", `{"status":"OK","ttl":"soon"}`, true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "", `{"status":"OK","ttl":"0s"}`, true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "", `{"status":"OK","ttl":"-5m"}`, true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "", `{"status":"OK","expires":"2000-01-01T00:00:00Z"}`, true).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, "", `{`, true).Code)
+
+	res = do(http.MethodPost, "", "", true)
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+	assert.Equal(t, "GET, PUT, DELETE", res.Header().Get("Allow"))
+
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, "", "", true).Code)
+	res = do(http.MethodDelete, "?check=postgres", "", true)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{}`, res.Body.String())
+
+	assert.Equal(t, StatusOK, h.Measure(context.Background()).Status)
+}
+
+func TestHealth_OverrideCheck_DependsOn(t *testing.T) {
+	var calls atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name:  "network",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:      "postgres",
+		DependsOn: []string{"network"},
+		Check: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusSkipped, c.Results["postgres"].Status)
+
+	require.NoError(t, h.OverrideCheck("network", Override{Status: StatusOK, Reason: "known issue"}))
+
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Equal(t, StatusOK, c.Results["postgres"].Status)
+	assert.Equal(t, int32(1), calls.Load())
+
+	h.mu.Lock()
+	assert.Empty(t, h.failedDependency(h.checks["postgres"]))
+	h.mu.Unlock()
+
+	h.Start()
+	defer h.Stop()
+
+	require.Eventually(t, func() bool { return calls.Load() > 1 }, time.Second, 5*time.Millisecond)
+}
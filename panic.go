@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+)
+
+// maxStackFrames is the number of the frames kept in the stack trace of the panicking check
+const maxStackFrames = 16
+
+// recovered wraps the check to return PanicError instead of crashing the process when the check panics
+func recovered(check CheckFunc) CheckFunc {
+	return func(ctx context.Context) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = &PanicError{Value: v, Stack: trimStack(debug.Stack())}
+			}
+		}()
+
+		return check(ctx)
+	}
+}
+
+// trimStack removes the goroutine header and the frames of the panic handling from the stack trace,
+// keeping at most maxStackFrames frames starting from the one that panicked
+func trimStack(stack []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+
+	// every frame takes two lines, the function and its file position
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			lines = lines[min(i+2, len(lines)):]
+			break
+		}
+	}
+
+	if len(lines) > 2*maxStackFrames {
+		lines = lines[:2*maxStackFrames]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func panickingCheck(context.Context) error {
+	var m map[string]int
+	m["boom"]++
+	return nil
+}
+
+func TestHealth_Measure_Panic(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	h, err := New(
+		WithTracerProvider(tp, "test"),
+		WithChecks(Config{
+			Name:  "broken",
+			Check: panickingCheck,
+		}, Config{
+			Name:      "optional",
+			SkipOnErr: true,
+			Check:     func(context.Context) error { panic("optional is broken") },
+		}),
+	)
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.Equal(t, "check panicked: assignment to entry in nil map", c.Failures["broken"])
+
+	broken := c.Results["broken"]
+	assert.Equal(t, StatusUnavailable, broken.Status)
+	assert.Equal(t, 2, broken.Panics)
+	assert.True(t, strings.HasPrefix(broken.Stack, "github.com/hellofresh/health-go/v5.panickingCheck("), broken.Stack)
+
+	optional := c.Results["optional"]
+	assert.Equal(t, StatusPartiallyAvailable, optional.Status)
+	assert.Equal(t, "check panicked: optional is broken", optional.Error)
+	assert.Equal(t, 2, optional.Panics)
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "broken" {
+			span = s
+		}
+	}
+	require.NotNil(t, span)
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), attribute.Bool("panicked", true))
+	require.Len(t, span.Events(), 1)
+	assert.Contains(t, span.Events()[0].Attributes, attribute.String("exception.stacktrace", broken.Stack))
+}
+
+func TestHealth_Measure_PanicNotRetried(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "broken",
+		Retry: &RetryPolicy{MaxAttempts: 3},
+		Check: panickingCheck,
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, 1, c.Results["broken"].Attempts)
+}
+
+func TestHealth_Handler_PanicWithoutFailureDetails(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "broken",
+		Check: panickingCheck,
+	}), WithFailureDetails(false))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	h.Handler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "http://localhost/status", nil))
+
+	var c Check
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &c))
+	assert.Empty(t, c.Results["broken"].Stack)
+	assert.Equal(t, 1, c.Results["broken"].Panics)
+}
+
+func TestRecovered(t *testing.T) {
+	err := recovered(func(context.Context) error { return errors.New(checkErr) })(context.Background())
+	assert.EqualError(t, err, checkErr)
+
+	err = recovered(func(context.Context) error { panic(42) })(context.Background())
+
+	var p *PanicError
+	require.ErrorAs(t, err, &p)
+	assert.Equal(t, 42, p.Value)
+	assert.EqualError(t, err, "check panicked: 42")
+	assert.NotContains(t, p.Stack, "runtime/debug.Stack")
+	assert.False(t, strings.HasPrefix(p.Stack, "goroutine "), p.Stack)
+	assert.LessOrEqual(t, strings.Count(p.Stack, "\n"), 2*maxStackFrames-1)
+}
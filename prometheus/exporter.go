@@ -0,0 +1,147 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultNamespace = "health"
+
+// statuses are the summary statuses exported by the status gauge
+var statuses = []health.Status{
+	health.StatusOK,
+	health.StatusPartiallyAvailable,
+	health.StatusUnavailable,
+}
+
+// Config is the Prometheus exporter configuration settings container.
+type Config struct {
+	// Namespace is the prefix of the metric names.
+	// If not set - "health"
+	Namespace string
+	// Buckets are the buckets of the check duration histogram, in seconds.
+	// If not set - prometheus.DefBuckets
+	Buckets []float64
+	// ConstLabels are the labels added to every metric.
+	ConstLabels prometheus.Labels
+}
+
+// Exporter exports the check results as Prometheus metrics. It implements health.Observer,
+// so it is fed directly by Health with health.WithObserver.
+type Exporter struct {
+	up       *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	failures *prometheus.CounterVec
+	timeouts *prometheus.CounterVec
+	panics   *prometheus.CounterVec
+	status   *prometheus.GaugeVec
+}
+
+// New creates new Prometheus exporter and registers its collectors on the registerer:
+// - <namespace>_check_up - whether the check is passing, by check name
+// - <namespace>_check_duration_seconds - histogram of the check durations, by check name, of the executed checks only
+// - <namespace>_check_failures_total - number of the check failures, by check name
+// - <namespace>_check_timeouts_total - number of the check timeouts, by check name
+// - <namespace>_check_panics_total - number of the check panics, by check name
+// - <namespace>_status - summary status, set to 1 for the current status, by probe kind and status
+func New(reg prometheus.Registerer, config Config) (*Exporter, error) {
+	if config.Namespace == "" {
+		config.Namespace = defaultNamespace
+	}
+	if config.Buckets == nil {
+		config.Buckets = prometheus.DefBuckets
+	}
+
+	e := &Exporter{
+		up: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_up",
+			Help:        "Whether the health check is passing (1) or failing (0).",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_duration_seconds",
+			Help:        "Duration of the health check executions.",
+			Buckets:     config.Buckets,
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_failures_total",
+			Help:        "Number of the failed health check executions.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		timeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_timeouts_total",
+			Help:        "Number of the health check executions that timed out.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_panics_total",
+			Help:        "Number of the health check executions that panicked.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		status: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   config.Namespace,
+			Name:        "status",
+			Help:        "Summary health status, 1 for the current status and 0 for the others.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"probe", "status"}),
+	}
+
+	for _, c := range []prometheus.Collector{e.up, e.duration, e.failures, e.timeouts, e.panics, e.status} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("could not register health metrics: %w", err)
+		}
+	}
+
+	return e, nil
+}
+
+// ObserveCheck implements health.Observer.
+func (e *Exporter) ObserveCheck(_ context.Context, name string, r health.Result) {
+	if r.Executed() {
+		e.duration.WithLabelValues(name).Observe(r.Duration.Seconds())
+	}
+	if r.TimedOut() {
+		e.timeouts.WithLabelValues(name).Inc()
+	}
+	if r.Stack != "" {
+		e.panics.WithLabelValues(name).Inc()
+	}
+
+	if r.Status == health.StatusOK {
+		e.up.WithLabelValues(name).Set(1)
+		return
+	}
+
+	e.up.WithLabelValues(name).Set(0)
+	e.failures.WithLabelValues(name).Inc()
+}
+
+// ObserveUnregister implements health.UnregisterObserver, it deletes the series of the unregistered check.
+func (e *Exporter) ObserveUnregister(_ context.Context, name string) {
+	e.up.DeleteLabelValues(name)
+	e.duration.DeleteLabelValues(name)
+	e.failures.DeleteLabelValues(name)
+	e.timeouts.DeleteLabelValues(name)
+	e.panics.DeleteLabelValues(name)
+}
+
+// ObserveStatus implements health.Observer.
+func (e *Exporter) ObserveStatus(_ context.Context, probe health.Probe, s health.Status) {
+	for _, status := range statuses {
+		value := 0.0
+		if status == s {
+			value = 1
+		}
+
+		e.status.WithLabelValues(string(probe), string(status)).Set(value)
+	}
+}
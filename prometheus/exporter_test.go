@@ -0,0 +1,114 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hellofresh/health-go/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+
+	_, err := New(reg, Config{})
+	require.NoError(t, err)
+
+	_, err = New(reg, Config{})
+	require.Error(t, err, "registering the same metrics twice should fail")
+
+	_, err = New(reg, Config{Namespace: "other"})
+	require.NoError(t, err)
+}
+
+func TestExporter(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	exporter, err := New(reg, Config{Buckets: []float64{0.5}})
+	require.NoError(t, err)
+
+	h, err := health.New(health.WithObserver(exporter), health.WithChecks(health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, health.Config{
+		Name:  "redis",
+		Check: func(context.Context) error { return errors.New("redis") },
+	}, health.Config{
+		Name:  "broken",
+		Check: func(context.Context) error { panic("nil map") },
+	}, health.Config{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Check: func(context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP health_check_up Whether the health check is passing (1) or failing (0).
+# TYPE health_check_up gauge
+health_check_up{check="broken"} 0
+health_check_up{check="postgres"} 1
+health_check_up{check="redis"} 0
+health_check_up{check="slow"} 0
+# HELP health_check_failures_total Number of the failed health check executions.
+# TYPE health_check_failures_total counter
+health_check_failures_total{check="broken"} 2
+health_check_failures_total{check="redis"} 2
+health_check_failures_total{check="slow"} 2
+# HELP health_check_panics_total Number of the health check executions that panicked.
+# TYPE health_check_panics_total counter
+health_check_panics_total{check="broken"} 2
+# HELP health_check_timeouts_total Number of the health check executions that timed out.
+# TYPE health_check_timeouts_total counter
+health_check_timeouts_total{check="slow"} 1
+# HELP health_status Summary health status, 1 for the current status and 0 for the others.
+# TYPE health_status gauge
+health_status{probe="",status="OK"} 0
+health_status{probe="",status="Partially Available"} 0
+health_status{probe="",status="Unavailable"} 1
+`), "health_check_up", "health_check_failures_total", "health_check_timeouts_total", "health_check_panics_total", "health_status")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, testutil.CollectAndCount(reg, "health_check_duration_seconds"))
+}
+
+func TestExporter_Unregister(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	exporter, err := New(reg, Config{})
+	require.NoError(t, err)
+
+	h, err := health.New(health.WithObserver(exporter), health.WithChecks(health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, health.Config{
+		Name:    "tenant-db",
+		Timeout: 10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	require.NoError(t, h.Unregister("tenant-db"))
+
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP health_check_up Whether the health check is passing (1) or failing (0).
+# TYPE health_check_up gauge
+health_check_up{check="postgres"} 1
+`), "health_check_up", "health_check_failures_total", "health_check_timeouts_total")
+	require.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(reg, "health_check_duration_seconds"))
+}
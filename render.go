@@ -0,0 +1,347 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Format is the format of the HTTP handlers response
+type Format string
+
+// Supported response formats
+const (
+	// FormatJSON is the Check rendered as application/json.
+	FormatJSON Format = "json"
+	// FormatHealthJSON is the application/health+json format defined by the
+	// "Health Check Response Format for HTTP APIs" IETF draft.
+	FormatHealthJSON Format = "health+json"
+	// FormatText is the terse text/plain report with one line per check.
+	FormatText Format = "text"
+	// FormatHTML is the self-contained text/html status page.
+	FormatHTML Format = "html"
+)
+
+type (
+	// renderer renders the check summary in one of the supported formats.
+	renderer struct {
+		contentType string
+		render      func(Check) ([]byte, error)
+	}
+
+	// healthResponse is the application/health+json response body.
+	healthResponse struct {
+		Status    string                          `json:"status"`
+		Output    string                          `json:"output,omitempty"`
+		ReleaseID string                          `json:"releaseId,omitempty"`
+		ServiceID string                          `json:"serviceId,omitempty"`
+		Notes     []string                        `json:"notes,omitempty"`
+		Checks    map[string][]healthCheckDetails `json:"checks,omitempty"`
+	}
+
+	// healthCheckDetails is the application/health+json check details.
+	healthCheckDetails struct {
+		ComponentID   string  `json:"componentId"`
+		ObservedValue float64 `json:"observedValue"`
+		ObservedUnit  string  `json:"observedUnit"`
+		Status        string  `json:"status"`
+		Time          string  `json:"time,omitempty"`
+		Output        string  `json:"output,omitempty"`
+	}
+)
+
+var renderers = map[Format]renderer{
+	FormatJSON: {
+		contentType: "application/json",
+		render:      renderJSON,
+	},
+	FormatHealthJSON: {
+		contentType: "application/health+json",
+		render:      renderHealthJSON,
+	},
+	FormatText: {
+		contentType: "text/plain",
+		render:      renderText,
+	},
+	FormatHTML: {
+		contentType: "text/html",
+		render:      renderHTML,
+	},
+}
+
+var htmlTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"time": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+	"class": func(s Status) string {
+		switch s {
+		case StatusOK:
+			return "ok"
+		case StatusPartiallyAvailable, StatusDisabled:
+			return "warn"
+		}
+		return "fail"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{with .Component.Name}}{{.}} - {{end}}{{.Status}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: .4em .8em; border-bottom: 1px solid #ddd; }
+.ok { color: #1a7f37; }
+.warn { color: #9a6700; }
+.fail { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>{{with .Component.Name}}{{.}} {{end}}<span class="{{class .Status}}">{{.Status}}</span></h1>
+<p>{{with .Component.Version}}Version {{.}}, {{end}}checked at {{time .Timestamp}}</p>
+{{- with .Reason}}
+<p class="fail">{{.}}</p>
+{{- end}}
+{{- with .Results}}
+<table>
+<tr><th>Check</th><th>Status</th><th>Duration</th><th>Last success</th><th>Error</th></tr>
+{{- range $name, $r := .}}
+<tr><td>{{$name}}</td><td class="{{class $r.Status}}">{{$r.Status}}</td><td>{{duration $r.Duration}}</td><td>{{with $r.LastSuccess}}{{time .}}{{end}}</td><td>{{$r.Error}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// serve measures the checks of the probe kind selected with the request query parameters
+// and writes the summary in the format negotiated with the request
+func (h *Health) serve(w http.ResponseWriter, r *http.Request, probe Probe) {
+	format, ok := h.negotiateFormat(r)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported response format %q", format), http.StatusBadRequest)
+		return
+	}
+	rr := renderers[format]
+
+	query := r.URL.Query()
+	c, err := h.MeasureSelected(r.Context(), Selector{
+		Probe:   probe,
+		Names:   query["check"],
+		Exclude: query["exclude"],
+		Tags:    query["tag"],
+	})
+	authorized := h.authorized(r)
+	if err != nil {
+		// do not disclose the registered checks to the requests that are not allowed to see them
+		if !authorized {
+			err = errors.New("unknown health checks")
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	code := h.statusCode(c)
+	switch {
+	case !authorized:
+		c = minimal(c)
+	case h.hideFailureDetails:
+		c = withoutFailureDetails(c)
+	}
+
+	w.Header().Set("Content-Type", rr.contentType)
+	data, err := rr.render(c)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// statusCode returns the HTTP status code of the response for the summary.
+// Unavailable summary caused by a check timeout is mapped with the StatusTimeout code if there is one.
+func (h *Health) statusCode(c Check) int {
+	if code, ok := h.statusCodes[StatusTimeout]; ok && c.Status == StatusUnavailable {
+		for _, r := range c.Results {
+			if r.Status == StatusTimeout && !r.SkipOnErr {
+				return code
+			}
+		}
+	}
+
+	if code, ok := h.statusCodes[c.Status]; ok {
+		return code
+	}
+
+	return http.StatusOK
+}
+
+// withoutFailureDetails returns the summary without the failure messages
+func withoutFailureDetails(c Check) Check {
+	c.Failures = nil
+
+	results := make(map[string]Result, len(c.Results))
+	for name, r := range c.Results {
+		r.Error = ""
+		r.Stack = ""
+		results[name] = r
+	}
+	c.Results = results
+
+	return c
+}
+
+// negotiateFormat returns the response format requested with the format query parameter, otherwise
+// the one with the highest preference in the Accept header, or the default format if none of the
+// accepted media types is supported. Reports whether the format is supported.
+func (h *Health) negotiateFormat(r *http.Request) (Format, bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		// the unescaped "+" of health+json is decoded as a space
+		format = strings.ReplaceAll(format, " ", "+")
+		_, ok := renderers[Format(format)]
+		return Format(format), ok
+	}
+
+	type accepted struct {
+		format Format
+		q      float64
+	}
+
+	var candidates []accepted
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q <= 0 {
+				continue
+			}
+		}
+
+		for format, rr := range renderers {
+			if rr.contentType == mediaType {
+				candidates = append(candidates, accepted{format: format, q: q})
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return h.format, true
+	}
+
+	// stable sort keeps the order of the header for the same preference
+	slices.SortStableFunc(candidates, func(a, b accepted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+
+	return candidates[0].format, true
+}
+
+// renderJSON renders the check summary as is
+func renderJSON(c Check) ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// renderHealthJSON renders the check summary in application/health+json format
+func renderHealthJSON(c Check) ([]byte, error) {
+	res := healthResponse{
+		Status:    healthStatus(c.Status),
+		Output:    c.Reason,
+		ReleaseID: c.Component.Version,
+		ServiceID: c.Component.Name,
+		Notes:     c.Component.Notes,
+	}
+
+	if len(c.Results) > 0 {
+		res.Checks = make(map[string][]healthCheckDetails, len(c.Results))
+	}
+	for name, r := range c.Results {
+		details := healthCheckDetails{
+			ComponentID:   name,
+			ObservedValue: float64(r.Duration) / float64(time.Millisecond),
+			ObservedUnit:  "ms",
+			Status:        healthStatus(r.Status),
+			Output:        r.Error,
+		}
+		if !r.StartedAt.IsZero() {
+			details.Time = r.StartedAt.Format(time.RFC3339Nano)
+		}
+		if r.Status == StatusDisabled {
+			details.Output = string(StatusDisabled)
+		}
+
+		res.Checks[fmt.Sprintf("%s:responseTime", name)] = []healthCheckDetails{details}
+	}
+
+	return json.Marshal(res)
+}
+
+// renderText renders the check summary as a terse text report with one line per check
+func renderText(c Check) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "status: %s\n", c.Status)
+	if c.Reason != "" {
+		fmt.Fprintf(&buf, "reason: %s\n", c.Reason)
+	}
+
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, name := range slices.Sorted(maps.Keys(c.Results)) {
+		r := c.Results[name]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, r.Status, formatDuration(r.Duration), r.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderHTML renders the check summary as a self-contained status page
+func renderHTML(c Check) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, c); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// formatDuration formats the check duration with a precision that is meaningful to a human
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+// healthStatus maps the status to the application/health+json one
+func healthStatus(s Status) string {
+	switch s {
+	case StatusOK:
+		return "pass"
+	case StatusPartiallyAvailable, StatusDisabled:
+		return "warn"
+	default:
+		return "fail"
+	}
+}
@@ -0,0 +1,257 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_negotiateFormat(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	for accept, expected := range map[string]Format{
+		"":                                FormatJSON,
+		"*/*":                             FormatJSON,
+		"image/png":                       FormatJSON,
+		"application/json":                FormatJSON,
+		"application/health+json":         FormatHealthJSON,
+		"text/plain":                      FormatText,
+		"text/html,application/xhtml+xml": FormatHTML,
+		"application/json, application/health+json":       FormatJSON,
+		"application/json;q=0.5, application/health+json": FormatHealthJSON,
+		"application/health+json;q=0, application/json":   FormatJSON,
+		"invalid;;, application/health+json":              FormatHealthJSON,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+		req.Header.Set("Accept", accept)
+
+		format, ok := h.negotiateFormat(req)
+		assert.True(t, ok)
+		assert.Equal(t, expected, format, "Accept: %s", accept)
+	}
+
+	h, err = New(WithFormat(FormatHealthJSON))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	format, _ := h.negotiateFormat(req)
+	assert.Equal(t, FormatHealthJSON, format)
+
+	req.Header.Set("Accept", "application/json")
+	format, _ = h.negotiateFormat(req)
+	assert.Equal(t, FormatJSON, format)
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/status?format=text", nil)
+	req.Header.Set("Accept", "application/json")
+	format, ok := h.negotiateFormat(req)
+	assert.True(t, ok)
+	assert.Equal(t, FormatText, format, "format query parameter should take precedence over Accept header")
+
+	for _, query := range []string{"format=health+json", "format=health%2Bjson"} {
+		req = httptest.NewRequest(http.MethodGet, "http://localhost/status?"+query, nil)
+		format, ok = h.negotiateFormat(req)
+		assert.True(t, ok, query)
+		assert.Equal(t, FormatHealthJSON, format, query)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/status?format=yaml", nil)
+	_, ok = h.negotiateFormat(req)
+	assert.False(t, ok)
+}
+
+func TestWithFormat(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, h.format)
+
+	h, err = New(WithFormat(FormatHealthJSON))
+	require.NoError(t, err)
+	assert.Equal(t, FormatHealthJSON, h.format)
+
+	_, err = New(WithFormat("yaml"))
+	require.Error(t, err)
+}
+
+func TestHealthHandlerHealthJSON(t *testing.T) {
+	h, err := New(WithComponent(Component{
+		Name:    "myservice",
+		Version: "v1.0",
+		Notes:   []string{"deployed by CI"},
+	}), WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:      "rabbitmq",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	req.Header.Set("Accept", "application/health+json")
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/health+json", res.Header().Get("Content-Type"))
+
+	var body healthResponse
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "warn", body.Status)
+	assert.Equal(t, "v1.0", body.ReleaseID)
+	assert.Equal(t, "myservice", body.ServiceID)
+	assert.Equal(t, []string{"deployed by CI"}, body.Notes)
+
+	require.Len(t, body.Checks, 2)
+	require.Len(t, body.Checks["postgres:responseTime"], 1)
+	postgres := body.Checks["postgres:responseTime"][0]
+	assert.Equal(t, "postgres", postgres.ComponentID)
+	assert.Equal(t, "pass", postgres.Status)
+	assert.Equal(t, "ms", postgres.ObservedUnit)
+	assert.NotEmpty(t, postgres.Time)
+	assert.Empty(t, postgres.Output)
+
+	rabbitmq := body.Checks["rabbitmq:responseTime"][0]
+	assert.Equal(t, "warn", rabbitmq.Status)
+	assert.Equal(t, checkErr, rabbitmq.Output)
+}
+
+func TestHealthStatus(t *testing.T) {
+	assert.Equal(t, "pass", healthStatus(StatusOK))
+	assert.Equal(t, "warn", healthStatus(StatusPartiallyAvailable))
+	assert.Equal(t, "warn", healthStatus(StatusDisabled))
+	assert.Equal(t, "fail", healthStatus(StatusUnavailable))
+	assert.Equal(t, "fail", healthStatus(StatusTimeout))
+	assert.Equal(t, "fail", healthStatus(StatusSkipped))
+}
+
+func TestHealthHandlerText(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:  "mongodb",
+		Check: func(context.Context) error { return errors.New("connection refused") },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status?format=text", nil)
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "text/plain", res.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "status: Unavailable", lines[0])
+	assert.Regexp(t, `^mongodb\s+Unavailable\s+\S+s\s+connection refused$`, lines[1])
+	assert.Regexp(t, `^postgres\s+OK\s+\S+s\s*$`, lines[2])
+}
+
+func TestHealthHandlerHTML(t *testing.T) {
+	h, err := New(WithComponent(Component{
+		Name:    "myservice",
+		Version: "v1.0",
+	}), WithChecks(Config{
+		Name:  "mongodb",
+		Check: func(context.Context) error { return errors.New("<script>") },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	req.Header.Set("Accept", "text/html")
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "text/html", res.Header().Get("Content-Type"))
+
+	body := res.Body.String()
+	assert.Contains(t, body, "<title>myservice - Unavailable</title>")
+	assert.Contains(t, body, "Version v1.0")
+	assert.Contains(t, body, "<td>mongodb</td>")
+	assert.Contains(t, body, "&lt;script&gt;", "check errors should be escaped")
+	assert.NotContains(t, body, "<script>")
+}
+
+func TestHealthHandlerUnsupportedFormat(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status?format=yaml", nil)
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
+
+func TestHealthHandlerStatusCodes(t *testing.T) {
+	var err1, err2 error
+	h, err := New(WithStatusCodes(map[Status]int{
+		StatusPartiallyAvailable: http.StatusMultiStatus,
+		StatusTimeout:            http.StatusGatewayTimeout,
+	}), WithChecks(Config{
+		Name:      "optional",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return err1 },
+	}, Config{
+		Name:    "critical",
+		Timeout: 10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			if err2 != nil {
+				time.Sleep(50 * time.Millisecond)
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	serve := func() int {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+		h.HandlerFunc(res, req)
+		return res.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+
+	err1 = errors.New("optional")
+	assert.Equal(t, http.StatusMultiStatus, serve())
+
+	err2 = errors.New("critical")
+	assert.Equal(t, http.StatusGatewayTimeout, serve())
+}
+
+func TestHealthHandlerWithoutFailureDetails(t *testing.T) {
+	h, err := New(WithFailureDetails(false), WithChecks(Config{
+		Name:  "mongodb",
+		Check: func(context.Context) error { return errors.New("dial tcp 10.0.0.12:27017: connection refused") },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	h.HandlerFunc(res, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.NotContains(t, res.Body.String(), "10.0.0.12")
+
+	var body Check
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUnavailable, body.Status)
+	assert.Empty(t, body.Failures)
+	assert.Equal(t, StatusUnavailable, body.Results["mongodb"].Status)
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+// RetryPolicy defines how a failed check is retried within its timeout.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of check executions, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled on every next retry.
+	// If not set - 100 milliseconds
+	InitialBackoff time.Duration
+	// MaxBackoff is the max delay between two attempts.
+	// If not set - 2 seconds
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the delay, between 0 and 1, that is randomly subtracted from it,
+	// so that the retries of several instances are spread in time.
+	Jitter float64
+	// Retryable reports whether the check failed with the error is worth retrying.
+	// If not set - all the errors are retried. Degraded results and panics are never retried.
+	Retryable func(error) bool
+}
+
+// do executes the check according to the policy until it succeeds, the attempts are exhausted or
+// the next attempt would not fit before the deadline. Nil policy executes the check once.
+func (p *RetryPolicy) do(ctx context.Context, deadline time.Time, check CheckFunc, attempts *atomic.Int32) error {
+	for attempt := 1; ; attempt++ {
+		attempts.Store(int32(attempt))
+
+		err := check(ctx)
+		if err == nil || !p.retryable(attempt, err) {
+			return err
+		}
+
+		backoff := p.backoff(attempt)
+		if time.Now().Add(backoff).After(deadline) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether the check should be executed again after the failed attempt
+func (p *RetryPolicy) retryable(attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts || isDegraded(err) || asPanic(err) != nil {
+		return false
+	}
+
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// backoff returns the delay after the given attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	initial, maxBackoff := p.InitialBackoff, p.MaxBackoff
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	backoff := initial
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxBackoff)
+
+	if p.Jitter > 0 {
+		backoff -= time.Duration(rand.Float64() * min(p.Jitter, 1) * float64(backoff))
+	}
+
+	return backoff
+}
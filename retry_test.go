@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	}
+
+	assert.Equal(t, 10*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 20*time.Millisecond, p.backoff(2))
+	assert.Equal(t, 40*time.Millisecond, p.backoff(3))
+	assert.Equal(t, 50*time.Millisecond, p.backoff(4))
+	assert.Equal(t, 50*time.Millisecond, p.backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		backoff := p.backoff(2)
+		assert.GreaterOrEqual(t, backoff, 10*time.Millisecond)
+		assert.LessOrEqual(t, backoff, 20*time.Millisecond)
+	}
+
+	assert.Equal(t, defaultInitialBackoff, (&RetryPolicy{}).backoff(1))
+}
+
+func TestHealth_MeasureRetry(t *testing.T) {
+	errTransient := errors.New("connection reset by peer")
+	errPermanent := errors.New("authentication failed")
+
+	var flakyCalls, permanentCalls, slowCalls int
+	h, err := New(WithChecks(Config{
+		Name: "flaky",
+		Retry: &RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+		},
+		Check: func(context.Context) error {
+			flakyCalls++
+			if flakyCalls < 3 {
+				return errTransient
+			}
+			return nil
+		},
+	}, Config{
+		Name: "permanent",
+		Retry: &RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+			Retryable: func(err error) bool {
+				return errors.Is(err, errTransient)
+			},
+		},
+		Check: func(context.Context) error {
+			permanentCalls++
+			return errPermanent
+		},
+	}, Config{
+		Name:    "slow",
+		Timeout: 100 * time.Millisecond,
+		Retry: &RetryPolicy{
+			MaxAttempts:    10,
+			InitialBackoff: 40 * time.Millisecond,
+			MaxBackoff:     40 * time.Millisecond,
+		},
+		Check: func(context.Context) error {
+			slowCalls++
+			return errTransient
+		},
+	}))
+	require.NoError(t, err)
+
+	result := h.Measure(context.Background())
+
+	assert.Equal(t, StatusOK, result.Results["flaky"].Status)
+	assert.Equal(t, 3, result.Results["flaky"].Attempts)
+
+	assert.Equal(t, StatusUnavailable, result.Results["permanent"].Status)
+	assert.Equal(t, 1, result.Results["permanent"].Attempts)
+	assert.Equal(t, 1, permanentCalls)
+
+	// attempts at 0, 40 and 80ms fit into the timeout, the next one would not
+	assert.Equal(t, StatusUnavailable, result.Results["slow"].Status)
+	assert.Equal(t, errTransient.Error(), result.Results["slow"].Error)
+	assert.Equal(t, 3, result.Results["slow"].Attempts)
+	assert.Equal(t, 3, slowCalls)
+}
@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+const defaultInterval = 10 * time.Second
+
+var errNotRunYet = errors.New("health check has not run yet")
+
+// Start runs every registered check in the background, each one on its own interval.
+// Once started, Measure and the HTTP handlers return the last cached results instantly
+// instead of running the checks on every call.
+func (h *Health) Start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.started {
+		return
+	}
+
+	h.started = true
+	h.sem = make(chan struct{}, h.maxConcurrent)
+	for _, c := range h.checks {
+		if !h.states[c.Name].disabled {
+			h.schedule(c)
+		}
+	}
+}
+
+// Stop stops running the checks in the background. The contexts of the running checks are cancelled
+// and Stop waits for them to return, their results are discarded. Measure runs the checks on every call again after Stop.
+func (h *Health) Stop() {
+	h.mu.Lock()
+	if !h.started {
+		h.mu.Unlock()
+		return
+	}
+
+	h.started = false
+	for name, cancel := range h.cancels {
+		cancel()
+		delete(h.cancels, name)
+	}
+	h.mu.Unlock()
+
+	h.wg.Wait()
+}
+
+// schedule starts the background loop of the check, must be called with h.mu held
+func (h *Health) schedule(c Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancels[c.Name] = cancel
+	sem := h.sem
+	state := h.states[c.Name]
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		tracer := h.tp.Tracer(h.instrumentationName)
+		ticker := time.NewTicker(c.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sem <- struct{}{}:
+			}
+
+			h.mu.Lock()
+			if ctx.Err() != nil {
+				h.mu.Unlock()
+				<-sem
+				return
+			}
+			dep := h.failedDependency(c)
+			stuck, isStuck := h.stuckResult(c)
+			h.mu.Unlock()
+
+			var res checkResult
+			switch {
+			case dep != "":
+				res = skippedResult(c, dep)
+			case isStuck:
+				res = stuck
+			default:
+				res = runCheck(ctx, tracer, c)
+			}
+			<-sem
+
+			h.mu.Lock()
+			if ctx.Err() != nil {
+				// the check was cancelled by Stop, Disable, Unregister or Replace, so the result is not a real one
+				h.mu.Unlock()
+				return
+			}
+			res = h.storeResult(state, res)
+			r := h.newResult(res)
+			all, _ := h.newPlan(Selector{})
+			status := h.reportedStatus("", h.cachedResults(all.run))
+			h.notifyStatus("", status)
+			// startup-only checks are not needed anymore once they have passed
+			done := c.startupOnly() && state.startupPassed
+			h.mu.Unlock()
+
+			h.observeCheck(ctx, c.Name, r)
+			h.observeStatus(ctx, "", status)
+
+			if done {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// unschedule stops the background loop of the check if any, must be called with h.mu held
+func (h *Health) unschedule(name string) {
+	if cancel, ok := h.cancels[name]; ok {
+		cancel()
+		delete(h.cancels, name)
+	}
+}
+
+// cachedResults returns the last results of the given checks, must be called with h.mu held
+func (h *Health) cachedResults(checks []Config) []checkResult {
+	results := make([]checkResult, 0, len(checks))
+	for _, c := range checks {
+		state, ok := h.states[c.Name]
+		if !ok {
+			// check was unregistered in the meantime
+			continue
+		}
+
+		if state.last != nil {
+			results = append(results, *state.last)
+			continue
+		}
+
+		results = append(results, checkResult{
+			name:      c.Name,
+			skipOnErr: c.SkipOnErr,
+			err:       errNotRunYet,
+			startedAt: time.Now(),
+		})
+	}
+
+	return results
+}
+
+// startupOnly reports whether the check belongs to the startup probe only
+func (c Config) startupOnly() bool {
+	return len(c.Probes) > 0 && !slices.ContainsFunc(c.Probes, func(p Probe) bool {
+		return p != ProbeStartup
+	})
+}
@@ -0,0 +1,238 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_StartStop(t *testing.T) {
+	var calls atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name:     "check",
+		Interval: 50 * time.Millisecond,
+		Check: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Start()
+	h.Start()
+
+	require.Eventually(t, func() bool {
+		return calls.Load() >= 3
+	}, time.Second, 10*time.Millisecond)
+
+	before := calls.Load()
+	result := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, result.Status)
+	assert.Empty(t, result.Failures)
+	assert.Less(t, result.Age, 100*time.Millisecond)
+	assert.LessOrEqual(t, calls.Load()-before, int32(1), "measure should not run the check when started")
+
+	h.Stop()
+	h.Stop()
+
+	stopped := calls.Load()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, stopped, calls.Load(), "check should not run after stop")
+
+	h.Measure(context.Background())
+	assert.Equal(t, stopped+1, calls.Load(), "measure should run the check after stop")
+}
+
+func TestHealth_StartNegativeInterval(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:     "check",
+		Interval: -time.Second,
+		Check:    func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, h.Replace(Config{
+		Name:     "check",
+		Interval: -time.Second,
+		Check:    func(context.Context) error { return nil },
+	}))
+	assert.Equal(t, defaultInterval, h.checks["check"].Interval)
+
+	// the scheduler must not panic on the ticker interval
+	h.Start()
+	defer h.Stop()
+
+	require.NoError(t, h.Register(Config{
+		Name:     "other",
+		Interval: -time.Second,
+		Check:    func(context.Context) error { return nil },
+	}))
+	assert.Equal(t, defaultInterval, h.checks["other"].Interval)
+}
+
+func TestHealth_StartCachedResults(t *testing.T) {
+	release := make(chan struct{})
+	h, err := New(WithChecks(Config{
+		Name:     "slow",
+		Interval: time.Hour,
+		Check: func(context.Context) error {
+			<-release
+			return errors.New("slow")
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Start()
+	defer h.Stop()
+
+	result := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, errNotRunYet.Error(), result.Failures["slow"])
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return h.Measure(context.Background()).Failures["slow"] == "slow"
+	}, time.Second, 10*time.Millisecond)
+
+	err = h.Register(Config{
+		Name:     "late",
+		Interval: time.Hour,
+		Check:    func(context.Context) error { return nil },
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, ok := h.Measure(context.Background()).Failures["late"]
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHealth_StartStartupChecks(t *testing.T) {
+	var calls atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name:     "startup",
+		Probes:   []Probe{ProbeStartup},
+		Interval: 10 * time.Millisecond,
+		Check: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Start()
+	defer h.Stop()
+
+	require.Eventually(t, func() bool {
+		return h.MeasureStartup(context.Background()).Status == StatusOK && calls.Load() > 0
+	}, time.Second, 10*time.Millisecond)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load(), "startup check should stop running once it has passed")
+}
+
+func TestHealth_StartDisableUnregister(t *testing.T) {
+	var calls atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name:     "check",
+		Interval: 10 * time.Millisecond,
+		Check: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Start()
+	defer h.Stop()
+
+	require.Eventually(t, func() bool { return calls.Load() > 0 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, h.Disable("check"))
+	// let the run started before disabling finish
+	time.Sleep(10 * time.Millisecond)
+	disabled := calls.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, disabled, calls.Load(), "disabled check should not run in the background")
+	assert.Equal(t, StatusDisabled, h.Measure(context.Background()).Results["check"].Status)
+
+	require.NoError(t, h.Enable("check"))
+	require.Eventually(t, func() bool { return calls.Load() > disabled }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, h.Unregister("check"))
+	time.Sleep(10 * time.Millisecond)
+	unregistered := calls.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, unregistered, calls.Load(), "unregistered check should not run in the background")
+	assert.Empty(t, h.Measure(context.Background()).Results)
+}
+
+func TestHealth_StartDisableRunning(t *testing.T) {
+	started := make(chan struct{}, 1)
+	events := make(chan Status, 10)
+	h, err := New(
+		WithChecks(Config{
+			Name:     "check",
+			Interval: time.Hour,
+			Check: func(ctx context.Context) error {
+				started <- struct{}{}
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}),
+		WithCheckStatusListener(func(_ string, _, next Status) { events <- next }),
+	)
+	require.NoError(t, err)
+
+	h.Start()
+	defer h.Stop()
+
+	<-started
+	require.NoError(t, h.Disable("check"))
+	// let the cancelled run return
+	time.Sleep(20 * time.Millisecond)
+
+	h.mu.Lock()
+	state := h.states["check"]
+	assert.Nil(t, state.last, "cancelled run must not be stored")
+	assert.Zero(t, state.consecutiveFailures)
+	assert.Nil(t, state.history)
+	h.mu.Unlock()
+
+	require.NoError(t, h.Enable("check"))
+	<-started
+	assert.Zero(t, h.Measure(context.Background()).Results["check"].ConsecutiveFailures)
+	assert.Empty(t, events, "check listeners must not be notified of the cancelled run")
+}
+
+func TestHealth_StopRunning(t *testing.T) {
+	started := make(chan struct{})
+	events := make(chan Status, 10)
+	h, err := New(
+		WithChecks(Config{
+			Name:     "check",
+			Interval: time.Hour,
+			Check: func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}),
+		WithCheckStatusListener(func(_ string, _, next Status) { events <- next }),
+	)
+	require.NoError(t, err)
+
+	h.Start()
+	<-started
+	h.Stop()
+
+	entries, err := h.History("check", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, entries, "cancelled run must not be stored")
+	assert.Empty(t, events, "check listeners must not be notified of the cancelled run")
+}
@@ -0,0 +1,37 @@
+package health
+
+import "time"
+
+// stuckRun is the check execution that is still running after its timeout.
+type stuckRun struct {
+	since time.Time
+	// done is closed once the execution finishes
+	done <-chan struct{}
+}
+
+// stuckResult returns the timeout result for the check which previous execution is still running
+// after its timeout, so that the executions of the stuck check do not pile up. Reports whether
+// the check is stuck. Must be called with h.mu held.
+func (h *Health) stuckResult(c Config) (checkResult, bool) {
+	state, ok := h.states[c.Name]
+	if !ok || state.stuck == nil {
+		return checkResult{}, false
+	}
+
+	select {
+	case <-state.stuck.done:
+		state.stuck = nil
+		return checkResult{}, false
+	default:
+	}
+
+	now := time.Now()
+
+	return checkResult{
+		name:      c.Name,
+		skipOnErr: c.SkipOnErr,
+		timedOut:  true,
+		startedAt: now,
+		stuckFor:  now.Sub(state.stuck.since),
+	}, true
+}
@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_Measure_CheckContextDeadline(t *testing.T) {
+	var executions atomic.Int32
+	h, err := New(WithChecks(Config{
+		Name:    "slow",
+		Timeout: 50 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			executions.Add(1)
+
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	require.NoError(t, err)
+
+	for range 3 {
+		c := h.Measure(context.Background())
+		assert.Equal(t, string(StatusTimeout), c.Failures["slow"])
+		assert.False(t, c.Results["slow"].Stuck)
+
+		// let the check return on the context cancellation
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, int32(3), executions.Load())
+}
+
+func TestHealth_Measure_StuckCheck(t *testing.T) {
+	var executions atomic.Int32
+	release := make(chan struct{})
+	h, err := New(WithChecks(Config{
+		Name:    "stuck",
+		Timeout: 10 * time.Millisecond,
+		Check: func(context.Context) error {
+			executions.Add(1)
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.Equal(t, StatusTimeout, c.Results["stuck"].Status)
+	assert.False(t, c.Results["stuck"].Stuck)
+
+	time.Sleep(20 * time.Millisecond)
+
+	// the stuck check is not executed again while the previous execution is running
+	for range 3 {
+		c = h.Measure(context.Background())
+		assert.Equal(t, string(StatusTimeout), c.Failures["stuck"])
+		assert.True(t, c.Results["stuck"].Stuck)
+		assert.GreaterOrEqual(t, c.Results["stuck"].StuckFor, 30*time.Millisecond)
+	}
+	assert.Equal(t, int32(1), executions.Load())
+
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.False(t, c.Results["stuck"].Stuck)
+	assert.Equal(t, int32(2), executions.Load())
+}
+
+func TestHealth_Start_StuckCheck(t *testing.T) {
+	var executions atomic.Int32
+	release := make(chan struct{})
+	h, err := New(WithChecks(Config{
+		Name:     "stuck",
+		Timeout:  10 * time.Millisecond,
+		Interval: 20 * time.Millisecond,
+		Check: func(context.Context) error {
+			executions.Add(1)
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Start()
+	defer h.Stop()
+	defer close(release)
+
+	time.Sleep(100 * time.Millisecond)
+
+	c := h.Measure(context.Background())
+	assert.True(t, c.Results["stuck"].Stuck)
+	assert.Equal(t, int32(1), executions.Load())
+}